@@ -0,0 +1,66 @@
+package lrmap
+
+// Number is the constraint of value types that Add supports.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Add adds delta to the value of key (missing keys count as zero) and returns the sum.  It is
+// recorded as an addition in the redo log, so that merging operations on the same key (see
+// WithRedoCompaction and WithReplayChunk) keeps all deltas.  If a validator rejects the sum,
+// Add changes nothing and returns the current value.
+//
+// Add is a function rather than a method, since it constrains the value type.
+func Add[K comparable, V Number](m *LRMap[K, V], key K, delta V) V {
+	m.lock()
+	defer m.mu.Unlock()
+
+	m.add = addNumbers[V]
+
+	key = m.normalize(key)
+	m.syncKey(key)
+	data := m.writeMap.Load().data
+
+	old, _ := data.Get(key)
+	sum := old + delta
+
+	if m.writable() != nil || m.validate(key, sum) != nil {
+		return old
+	}
+
+	key = m.intern(key)
+	m.written(data, key, sum, true)
+	data.Set(key, sum)
+	m.log(operation[K, V]{typ: OpAdd, key: key, value: delta})
+
+	return sum
+}
+
+// WithAdd enables additions on a map before any call to Add, which ApplyOps requires to apply
+// OpAdd operations.
+func WithAdd[K comparable, V Number]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.add = addNumbers[V]
+	}
+}
+
+func addNumbers[V Number](a, b V) V { return a + b }
+
+// merge returns a single operation that has the effect of prev followed by op.
+func (m *LRMap[K, V]) merge(prev, op operation[K, V]) operation[K, V] {
+	if op.typ != OpAdd {
+		return op
+	}
+
+	switch prev.typ {
+	case OpSet:
+		return operation[K, V]{typ: OpSet, key: op.key, value: m.add(prev.value, op.value)}
+	case OpDelete, OpPurge:
+		// the key does not exist anymore, so there is nothing to add to
+		return operation[K, V]{typ: OpSet, key: op.key, value: op.value}
+	default:
+		return operation[K, V]{typ: OpAdd, key: op.key, value: m.add(prev.value, op.value)}
+	}
+}
@@ -0,0 +1,68 @@
+package lrmap
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	for name, opts := range map[string][]Option[string, int]{
+		"plain":      nil,
+		"compaction": {WithRedoCompaction[string, int]()},
+		"chunked":    {WithReplayChunk[string, int](1)},
+	} {
+		t.Run(name, func(t *testing.T) {
+			lrm := New(opts...)
+
+			rh := lrm.NewReadHandler()
+			defer rh.Close()
+
+			for round := 1; round <= 3; round++ {
+				lrm.Set("set", 10)
+				Add(lrm, "set", 1)
+				Add(lrm, "set", 2)
+
+				Add(lrm, "add", 1)
+				Add(lrm, "add", 1)
+
+				lrm.Delete("deleted")
+				Add(lrm, "deleted", 5)
+
+				lrm.Set("other", round)
+				lrm.Set("another", round)
+
+				if sum := Add(lrm, "add", 0); sum != 2*round {
+					t.Errorf("round %d: Add(add, 0) = %d, want %d", round, sum, 2*round)
+				}
+
+				lrm.Commit()
+
+				rh.Enter()
+				for key, want := range map[string]int{"set": 13, "add": 2 * round, "deleted": 5} {
+					if got := rh.Get(key); got != want {
+						t.Errorf("round %d: Get(%s) = %d, want %d", round, key, got, want)
+					}
+				}
+				rh.Leave()
+			}
+
+			if got := lrm.Get("add"); got != 6 {
+				t.Errorf("write map Get(add) = %d, want 6", got)
+			}
+		})
+	}
+}
+
+func TestAddHook(t *testing.T) {
+	var ops []Op[int, float64]
+
+	lrm := New(WithPreCommit(func(pending []Op[int, float64]) error {
+		ops = pending
+
+		return nil
+	}))
+
+	Add(lrm, 1, 0.5)
+	lrm.Commit()
+
+	if want := []Op[int, float64]{{Kind: OpAdd, Key: 1, Value: 0.5}}; len(ops) != 1 || ops[0] != want[0] {
+		t.Errorf("pre-commit hook saw %v, want %v", ops, want)
+	}
+}
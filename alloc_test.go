@@ -0,0 +1,96 @@
+package lrmap
+
+import (
+	"cmp"
+	"testing"
+)
+
+type smallStruct struct {
+	id    uint32
+	flags uint16
+	score float64
+}
+
+const allocKeys = 1024
+
+// setLoop writes to a fixed set of keys and commits regularly, so that the arenas reach their
+// final size early on and the redo log is recycled.
+func setLoop[V any](lrm *LRMap[int, V], n int, value func(int) V) {
+	for i := 0; i < n; i++ {
+		lrm.Set(i%allocKeys, value(i))
+
+		if i%allocKeys == allocKeys-1 {
+			lrm.Commit()
+		}
+	}
+}
+
+func TestSetZeroAllocs(t *testing.T) {
+	ints := New(WithRedoLogCapacity[int, int](allocKeys))
+	structs := New(WithRedoLogCapacity[int, smallStruct](allocKeys))
+
+	intValue := func(i int) int { return i }
+	structValue := func(i int) smallStruct { return smallStruct{id: uint32(i), flags: 1, score: 0.5} }
+
+	// warm up
+	setLoop(ints, 2*allocKeys, intValue)
+	setLoop(structs, 2*allocKeys, structValue)
+
+	if allocs := testing.AllocsPerRun(10, func() { setLoop(ints, allocKeys, intValue) }); allocs != 0 {
+		t.Errorf("Set() int/int, want 0 allocs, got %f per %d sets", allocs, allocKeys)
+	}
+
+	if allocs := testing.AllocsPerRun(10, func() { setLoop(structs, allocKeys, structValue) }); allocs != 0 {
+		t.Errorf("Set() int/smallStruct, want 0 allocs, got %f per %d sets", allocs, allocKeys)
+	}
+}
+
+func BenchmarkSet(b *testing.B) {
+	intValue := func(i int) int { return i }
+	structValue := func(i int) smallStruct { return smallStruct{id: uint32(i), flags: 1, score: 0.5} }
+
+	b.Run("int", func(b *testing.B) {
+		lrm := New(WithRedoLogCapacity[int, int](allocKeys))
+		setLoop(lrm, 2*allocKeys, intValue)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		setLoop(lrm, b.N, intValue)
+	})
+
+	b.Run("struct", func(b *testing.B) {
+		lrm := New(WithRedoLogCapacity[int, smallStruct](allocKeys))
+		setLoop(lrm, 2*allocKeys, structValue)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		setLoop(lrm, b.N, structValue)
+	})
+
+	b.Run("compacted", func(b *testing.B) {
+		lrm := New(WithRedoLogCapacity[int, int](allocKeys), WithRedoCompaction[int, int]())
+		setLoop(lrm, 2*allocKeys, intValue)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		setLoop(lrm, b.N, intValue)
+	})
+
+	b.Run("btree", func(b *testing.B) {
+		lrm := New(WithRedoLogCapacity[int, int](allocKeys), WithOrderedArena[int, int](cmp.Compare[int]))
+		setLoop(lrm, 2*allocKeys, intValue)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		setLoop(lrm, b.N, intValue)
+	})
+
+	b.Run("swiss", func(b *testing.B) {
+		lrm := New(WithRedoLogCapacity[int, int](allocKeys), WithSwissArena[int, int](nil))
+		setLoop(lrm, 2*allocKeys, intValue)
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		setLoop(lrm, b.N, intValue)
+	})
+}
@@ -0,0 +1,71 @@
+package lrmap
+
+type (
+	// Arena is the storage behind each of the two sides of an LRMap.  An LRMap only ever calls
+	// an Arena from one goroutine at a time: the writer while it owns the arena, or any number
+	// of readers while the arena is live, but never both.  Hence implementations need not be
+	// safe for concurrent writes, but must allow concurrent calls of Get, Len and Iterate.
+	Arena[K comparable, V any] interface {
+		Get(key K) (V, bool)
+		Contains(key K) bool
+		Set(key K, value V)
+		Delete(key K)
+		Len() int
+		Iterate(fn func(K, V) bool)
+		Clone() Arena[K, V]
+	}
+
+	// OrderedArena is an Arena that keeps its keys in order and supports range scans over the
+	// half-open interval [from, to).  Use an OrderedArena to enable ReadHandler.Range.
+	OrderedArena[K comparable, V any] interface {
+		Arena[K, V]
+		Range(from, to K, fn func(K, V) bool)
+	}
+
+	// DescendingArena is an OrderedArena that can also scan in descending key order, which
+	// enables ReadHandler.RangeDesc and ReadHandler.IterateDesc.  The arena of
+	// WithOrderedArena is a DescendingArena.
+	DescendingArena[K comparable, V any] interface {
+		OrderedArena[K, V]
+		RangeDesc(from, to K, fn func(K, V) bool)
+		IterateDesc(fn func(K, V) bool)
+	}
+
+	// MapArena is the default Arena, a plain Go map.
+	MapArena[K comparable, V any] map[K]V
+)
+
+func NewMapArena[K comparable, V any]() Arena[K, V] { return make(MapArena[K, V]) }
+
+func (a MapArena[K, V]) Get(key K) (V, bool) {
+	value, ok := a[key]
+
+	return value, ok
+}
+
+func (a MapArena[K, V]) Contains(key K) bool {
+	_, ok := a[key]
+
+	return ok
+}
+
+func (a MapArena[K, V]) Set(key K, value V) { a[key] = value }
+func (a MapArena[K, V]) Delete(key K)       { delete(a, key) }
+func (a MapArena[K, V]) Len() int           { return len(a) }
+
+func (a MapArena[K, V]) Iterate(fn func(K, V) bool) {
+	for key, value := range a {
+		if ok := fn(key, value); !ok {
+			return
+		}
+	}
+}
+
+func (a MapArena[K, V]) Clone() Arena[K, V] {
+	c := make(MapArena[K, V], len(a))
+	for key, value := range a {
+		c[key] = value
+	}
+
+	return c
+}
@@ -0,0 +1,45 @@
+package lrmap
+
+import "testing"
+
+// countingArena wraps a MapArena and counts the writes it receives.
+type countingArena[K comparable, V any] struct {
+	Arena[K, V]
+	writes *int
+}
+
+func (a countingArena[K, V]) Set(key K, value V) { *a.writes++; a.Arena.Set(key, value) }
+func (a countingArena[K, V]) Delete(key K)       { *a.writes++; a.Arena.Delete(key) }
+
+func TestWithArena(t *testing.T) {
+	var writes int
+
+	lrm := New(WithArena(func() Arena[string, int] {
+		return countingArena[string, int]{Arena: NewMapArena[string, int](), writes: &writes}
+	}))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", 1)
+	lrm.Set("b", 2)
+	lrm.Delete("a")
+	lrm.Commit()
+
+	// every operation is applied once to each side
+	if writes != 6 {
+		t.Errorf("want 6 writes to the arenas, got %d", writes)
+	}
+
+	rh.Enter()
+
+	if v, ok := rh.GetOK("b"); !ok || v != 2 {
+		t.Errorf("GetOK(b), want (2, true), got (%d, %t)", v, ok)
+	}
+
+	if n := rh.Len(); n != 1 {
+		t.Errorf("Len(), want 1, got %d", n)
+	}
+
+	rh.Leave()
+}
@@ -0,0 +1,51 @@
+package lrmap
+
+// asyncWrite is a write queued by SetAsync.  The queue is a lock-free stack, which producers
+// push to and the writer takes as a whole.
+type asyncWrite[K comparable, V any] struct {
+	key   K
+	value V
+	next  *asyncWrite[K, V]
+}
+
+// SetAsync queues a write of value under key without taking the writer lock, so that many
+// goroutines can write concurrently without contending for it.  The next commit applies the
+// queued writes in the order they have been queued (per goroutine) before it publishes, so they
+// are not visible to the writer methods (Get, Contains, ...) until then, and they are applied
+// after any writes made with Set in the meantime.  Writes that a validator rejects are dropped,
+// as are writes to a frozen or closed map.
+func (m *LRMap[K, V]) SetAsync(key K, value V) {
+	if m.writable() != nil {
+		return
+	}
+
+	w := &asyncWrite[K, V]{key: key, value: value, next: nil}
+
+	for {
+		w.next = m.async.Load()
+		if m.async.CompareAndSwap(w.next, w) {
+			return
+		}
+	}
+}
+
+// drainAsync applies the writes queued by SetAsync.  The caller must hold m.mu.
+func (m *LRMap[K, V]) drainAsync() {
+	top := m.async.Swap(nil)
+	if top == nil {
+		return
+	}
+
+	// the stack holds the latest write on top, so reverse it
+	var queue *asyncWrite[K, V]
+
+	for top != nil {
+		next := top.next
+		top.next = queue
+		queue, top = top, next
+	}
+
+	for w := queue; w != nil; w = w.next {
+		_ = m.trySet(w.key, w.value)
+	}
+}
@@ -0,0 +1,80 @@
+package lrmap
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestSetAsync(t *testing.T) {
+	lrm := New(WithValidator[int, int](func(_ int, v int) error {
+		if v < 0 {
+			return errors.New("negative")
+		}
+
+		return nil
+	}))
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 100; i++ {
+				lrm.SetAsync(g*100+i, i)
+			}
+
+			// the last write of a goroutine to a key wins
+			lrm.SetAsync(g*100, 1000)
+			lrm.SetAsync(g*100+1, -1)
+		}(g)
+	}
+
+	wg.Wait()
+
+	if _, ok := lrm.GetOK(0); ok {
+		t.Errorf("queued write visible before the commit")
+	}
+
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if n := rh.Len(); n != 800 {
+		t.Errorf("Len(), want 800, got %d", n)
+	}
+
+	for g := 0; g < 8; g++ {
+		if v := rh.Get(g * 100); v != 1000 {
+			t.Errorf("Get(%d), want 1000, got %d", g*100, v)
+		}
+
+		if v := rh.Get(g*100 + 1); v != 1 {
+			t.Errorf("Get(%d), want the rejected write dropped, got %d", g*100+1, v)
+		}
+
+		if v := rh.Get(g*100 + 99); v != 99 {
+			t.Errorf("Get(%d), want 99, got %d", g*100+99, v)
+		}
+	}
+}
+
+func TestSetAsyncClosed(t *testing.T) {
+	lrm := New[int, int]()
+	lrm.SetAsync(1, 1)
+
+	_ = lrm.Close()
+
+	lrm.SetAsync(2, 2)
+
+	if lrm.async.Load() != nil {
+		t.Errorf("writes queued on a closed map")
+	}
+}
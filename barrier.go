@@ -0,0 +1,88 @@
+package lrmap
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+const (
+	// maxWaitDelay is the longest pause between two polls of commits waiting for readers.
+	maxWaitDelay = 5 * time.Second
+
+	// maxBarrierDelay is the longest pause between two polls of Barrier, which bounds how late
+	// it notices a cancelled context.
+	maxBarrierDelay = 100 * time.Millisecond
+)
+
+// enteredReader is a handler that has been entered at the time of a snapshot.
+type enteredReader struct {
+	slot  *epochSlot
+	epoch uint64
+	owner *ReaderInfo
+}
+
+// Barrier waits until all readers that are entered at the time of the call have left, e.g. to
+// know when no reader can still see a value that has been removed from the read map.  It
+// neither takes the writer lock nor publishes anything.
+func (m *LRMap[K, V]) Barrier(ctx context.Context) error {
+	return m.awaitReaders(ctx, m.enteredReaders(), maxBarrierDelay, func(enteredReader) {}, nil)
+}
+
+func (m *LRMap[K, V]) enteredReaders() []enteredReader {
+	var readers []enteredReader
+
+	m.readHandlers.forEach(func(slot *epochSlot) {
+		if epoch := slot.epoch.Load(); epoch%2 == 1 {
+			readers = append(readers, enteredReader{slot: slot, epoch: epoch, owner: slot.owner.Load()})
+		}
+	})
+
+	return readers
+}
+
+// awaitReaders polls readers with exponential backoff until all of them have left the epoch
+// they had been in, and calls left for each one as soon as it has.  If waiting is not nil, it
+// is called with the remaining readers before each pause, and returns the readers to keep
+// waiting for.
+func (m *LRMap[K, V]) awaitReaders(
+	ctx context.Context,
+	readers []enteredReader,
+	maxDelay time.Duration,
+	left func(enteredReader),
+	waiting func([]enteredReader) []enteredReader,
+) error {
+	m.checkSelfWait(readers)
+
+	delay := time.Microsecond
+
+	for {
+		readers = slices.DeleteFunc(readers, func(r enteredReader) bool {
+			if r.slot.epoch.Load() == r.epoch {
+				return false
+			}
+
+			left(r)
+
+			return true
+		})
+
+		if len(readers) == 0 {
+			return nil
+		}
+
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if waiting != nil {
+			if readers = waiting(readers); len(readers) == 0 {
+				return nil
+			}
+		}
+
+		m.clock.Sleep(delay)
+
+		delay = min(delay*10, maxDelay)
+	}
+}
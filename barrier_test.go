@@ -0,0 +1,41 @@
+package lrmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBarrier(t *testing.T) {
+	lrm := New[int, int]()
+
+	if err := lrm.Barrier(context.Background()); err != nil {
+		t.Fatalf("Barrier() without readers: %v", err)
+	}
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := lrm.Barrier(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Barrier() with entered reader, want %v, got %v", context.DeadlineExceeded, err)
+	}
+
+	done := make(chan error)
+
+	go func() {
+		done <- lrm.Barrier(context.Background())
+	}()
+
+	time.Sleep(time.Millisecond)
+	rh.Leave()
+
+	if err := <-done; err != nil {
+		t.Errorf("Barrier(): %v", err)
+	}
+}
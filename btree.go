@@ -0,0 +1,441 @@
+package lrmap
+
+import "slices"
+
+// btreeDegree is the minimum number of children of an inner node (except the root).  Nodes
+// hold between btreeDegree-1 and 2*btreeDegree-1 items.
+const btreeDegree = 16
+
+const (
+	btreeMaxItems = 2*btreeDegree - 1
+	btreeMinItems = btreeDegree - 1
+)
+
+type (
+	// btree is an OrderedArena.  The implementation follows the classic in-memory B-tree
+	// that splits full nodes on the way down during insertion and grows too small nodes on the
+	// way down during deletion, so that no operation needs to walk back up the tree.
+	btree[K comparable, V any] struct {
+		compare func(K, K) int
+		root    *btreeNode[K, V]
+		length  int
+	}
+
+	btreeNode[K comparable, V any] struct {
+		items    []btreeItem[K, V]
+		children []*btreeNode[K, V]
+	}
+
+	btreeItem[K comparable, V any] struct {
+		key   K
+		value V
+	}
+)
+
+func newBTree[K comparable, V any](compare func(K, K) int) *btree[K, V] {
+	// nolint:exhaustivestruct
+	return &btree[K, V]{compare: compare}
+}
+
+func (t *btree[K, V]) Get(key K) (V, bool) {
+	for n := t.root; n != nil; {
+		i, found := n.find(key, t.compare)
+		if found {
+			return n.items[i].value, true
+		}
+
+		if n.leaf() {
+			break
+		}
+
+		n = n.children[i]
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+func (t *btree[K, V]) Contains(key K) bool {
+	for n := t.root; n != nil; {
+		i, found := n.find(key, t.compare)
+		if found {
+			return true
+		}
+
+		if n.leaf() {
+			break
+		}
+
+		n = n.children[i]
+	}
+
+	return false
+}
+
+func (t *btree[K, V]) Set(key K, value V) {
+	item := btreeItem[K, V]{key: key, value: value}
+
+	if t.root == nil {
+		// nolint:exhaustivestruct
+		t.root = &btreeNode[K, V]{items: []btreeItem[K, V]{item}}
+		t.length++
+
+		return
+	}
+
+	if len(t.root.items) >= btreeMaxItems {
+		median, right := t.root.split(btreeMaxItems / 2)
+		t.root = &btreeNode[K, V]{
+			items:    []btreeItem[K, V]{median},
+			children: []*btreeNode[K, V]{t.root, right},
+		}
+	}
+
+	if t.root.insert(item, t.compare) {
+		t.length++
+	}
+}
+
+func (t *btree[K, V]) Delete(key K) {
+	if t.root == nil {
+		return
+	}
+
+	if _, ok := t.root.remove(key, false, t.compare); ok {
+		t.length--
+	}
+
+	if len(t.root.items) == 0 {
+		if t.root.leaf() {
+			t.root = nil
+		} else {
+			t.root = t.root.children[0]
+		}
+	}
+}
+
+func (t *btree[K, V]) Len() int { return t.length }
+
+func (t *btree[K, V]) Iterate(fn func(K, V) bool) {
+	if t.root != nil {
+		t.root.ascend(nil, nil, fn, t.compare)
+	}
+}
+
+func (t *btree[K, V]) Range(from, to K, fn func(K, V) bool) {
+	if t.root != nil && t.compare(from, to) < 0 {
+		t.root.ascend(&from, &to, fn, t.compare)
+	}
+}
+
+func (t *btree[K, V]) RangeDesc(from, to K, fn func(K, V) bool) {
+	if t.root != nil && t.compare(from, to) < 0 {
+		t.root.descend(&from, &to, fn, t.compare)
+	}
+}
+
+func (t *btree[K, V]) IterateDesc(fn func(K, V) bool) {
+	if t.root != nil {
+		t.root.descend(nil, nil, fn, t.compare)
+	}
+}
+
+func (t *btree[K, V]) Clone() Arena[K, V] {
+	c := newBTree[K, V](t.compare)
+	c.length = t.length
+
+	if t.root != nil {
+		c.root = t.root.clone()
+	}
+
+	return c
+}
+
+// load fills the empty tree with items, which must be in ascending order without duplicates.
+// It builds the tree bottom-up in linear time instead of inserting the items one by one.
+func (t *btree[K, V]) load(items []btreeItem[K, V]) {
+	t.length = len(items)
+
+	if len(items) == 0 {
+		t.root = nil
+
+		return
+	}
+
+	// the capacity of a tree of height h is (2*btreeDegree)^h - 1
+	capacity := btreeMaxItems
+	for capacity < len(items) {
+		capacity = (capacity+1)*(btreeMaxItems+1) - 1
+	}
+
+	t.root = buildBTreeNode(items, capacity)
+}
+
+// buildBTreeNode builds a subtree of the height that holds at most capacity items.  Each inner
+// node gets as few children as possible, between which items are spread evenly, so that every
+// node but the root holds at least btreeMinItems items.
+func buildBTreeNode[K comparable, V any](items []btreeItem[K, V], capacity int) *btreeNode[K, V] {
+	if capacity == btreeMaxItems {
+		// nolint:exhaustivestruct
+		return &btreeNode[K, V]{items: slices.Clone(items)}
+	}
+
+	childCapacity := (capacity+1)/(btreeMaxItems+1) - 1
+
+	// k children take k-1 items as separators, so they hold len(items)+1-k items together
+	k := max(2, (len(items)+childCapacity+1)/(childCapacity+1))
+	size, extra := (len(items)+1)/k, (len(items)+1)%k
+
+	n := &btreeNode[K, V]{
+		items:    make([]btreeItem[K, V], 0, k-1),
+		children: make([]*btreeNode[K, V], 0, k),
+	}
+
+	for i := 0; i < k; i++ {
+		m := size - 1
+		if i < extra {
+			m++
+		}
+
+		n.children = append(n.children, buildBTreeNode(items[:m], childCapacity))
+		items = items[m:]
+
+		if i < k-1 {
+			n.items = append(n.items, items[0])
+			items = items[1:]
+		}
+	}
+
+	return n
+}
+
+func (n *btreeNode[K, V]) leaf() bool { return len(n.children) == 0 }
+
+// find returns the index of the first item whose key is not less than key, and whether that
+// item's key equals key.
+func (n *btreeNode[K, V]) find(key K, compare func(K, K) int) (int, bool) {
+	return slices.BinarySearchFunc(n.items, key, func(item btreeItem[K, V], key K) int {
+		return compare(item.key, key)
+	})
+}
+
+// split cuts n at item i and returns that item and a new node with everything right of it.
+func (n *btreeNode[K, V]) split(i int) (btreeItem[K, V], *btreeNode[K, V]) {
+	median := n.items[i]
+
+	// nolint:exhaustivestruct
+	right := &btreeNode[K, V]{items: slices.Clone(n.items[i+1:])}
+	clear(n.items[i:])
+	n.items = n.items[:i]
+
+	if !n.leaf() {
+		right.children = slices.Clone(n.children[i+1:])
+		clear(n.children[i+1:])
+		n.children = n.children[:i+1]
+	}
+
+	return median, right
+}
+
+// insert adds or replaces item in the subtree rooted at n, which must not be full.  It
+// reports whether the tree has grown by one item.
+func (n *btreeNode[K, V]) insert(item btreeItem[K, V], compare func(K, K) int) bool {
+	i, found := n.find(item.key, compare)
+	if found {
+		n.items[i] = item
+
+		return false
+	}
+
+	if n.leaf() {
+		n.items = slices.Insert(n.items, i, item)
+
+		return true
+	}
+
+	if len(n.children[i].items) >= btreeMaxItems {
+		median, right := n.children[i].split(btreeMaxItems / 2)
+		n.items = slices.Insert(n.items, i, median)
+		n.children = slices.Insert(n.children, i+1, right)
+
+		switch c := compare(item.key, median.key); {
+		case c == 0:
+			n.items[i] = item
+
+			return false
+		case c > 0:
+			i++
+		}
+	}
+
+	return n.children[i].insert(item, compare)
+}
+
+// remove deletes key (or, if max is set, the greatest item) from the subtree rooted at n and
+// returns the removed item, if any.
+func (n *btreeNode[K, V]) remove(key K, max bool, compare func(K, K) int) (btreeItem[K, V], bool) {
+	var (
+		i     int
+		found bool
+	)
+
+	if max {
+		if n.leaf() {
+			item := n.items[len(n.items)-1]
+			n.items[len(n.items)-1] = btreeItem[K, V]{} // nolint:exhaustivestruct
+			n.items = n.items[:len(n.items)-1]
+
+			return item, true
+		}
+
+		i = len(n.items)
+	} else {
+		i, found = n.find(key, compare)
+
+		if n.leaf() {
+			if !found {
+				return btreeItem[K, V]{}, false // nolint:exhaustivestruct
+			}
+
+			item := n.items[i]
+			n.items = slices.Delete(n.items, i, i+1)
+
+			return item, true
+		}
+	}
+
+	if len(n.children[i].items) <= btreeMinItems {
+		n.growChild(i)
+
+		// growing the child has moved items around, so start over at this node
+		return n.remove(key, max, compare)
+	}
+
+	if found {
+		// replace the item with its predecessor, which lives in a leaf of the left subtree
+		item := n.items[i]
+		n.items[i], _ = n.children[i].remove(key, true, compare)
+
+		return item, true
+	}
+
+	return n.children[i].remove(key, max, compare)
+}
+
+// growChild makes sure that child i has more than the minimum number of items, either by
+// stealing an item from a sibling or by merging with a sibling.
+func (n *btreeNode[K, V]) growChild(i int) {
+	child := n.children[i]
+
+	switch {
+	case i > 0 && len(n.children[i-1].items) > btreeMinItems:
+		left := n.children[i-1]
+		stolen := left.items[len(left.items)-1]
+		left.items = slices.Delete(left.items, len(left.items)-1, len(left.items))
+		child.items = slices.Insert(child.items, 0, n.items[i-1])
+		n.items[i-1] = stolen
+
+		if !left.leaf() {
+			child.children = slices.Insert(child.children, 0, left.children[len(left.children)-1])
+			left.children = slices.Delete(left.children, len(left.children)-1, len(left.children))
+		}
+	case i < len(n.items) && len(n.children[i+1].items) > btreeMinItems:
+		right := n.children[i+1]
+		stolen := right.items[0]
+		right.items = slices.Delete(right.items, 0, 1)
+		child.items = append(child.items, n.items[i])
+		n.items[i] = stolen
+
+		if !right.leaf() {
+			child.children = append(child.children, right.children[0])
+			right.children = slices.Delete(right.children, 0, 1)
+		}
+	default:
+		if i >= len(n.items) {
+			i--
+			child = n.children[i]
+		}
+
+		right := n.children[i+1]
+		child.items = append(child.items, n.items[i])
+		child.items = append(child.items, right.items...)
+		child.children = append(child.children, right.children...)
+		n.items = slices.Delete(n.items, i, i+1)
+		n.children = slices.Delete(n.children, i+1, i+2)
+	}
+}
+
+// ascend calls fn for all items in [from, to) in ascending order, where nil bounds are
+// unbounded.  It returns false if the iteration has been stopped.
+func (n *btreeNode[K, V]) ascend(from, to *K, fn func(K, V) bool, compare func(K, K) int) bool {
+	i := 0
+	if from != nil {
+		i, _ = n.find(*from, compare)
+	}
+
+	for ; i < len(n.items); i++ {
+		if !n.leaf() && !n.children[i].ascend(from, to, fn, compare) {
+			return false
+		}
+
+		item := n.items[i]
+		if to != nil && compare(item.key, *to) >= 0 {
+			return false
+		}
+
+		if !fn(item.key, item.value) {
+			return false
+		}
+	}
+
+	if !n.leaf() {
+		return n.children[len(n.children)-1].ascend(from, to, fn, compare)
+	}
+
+	return true
+}
+
+// descend is the mirror image of ascend: it calls fn for all items in [from, to) in descending
+// order.
+func (n *btreeNode[K, V]) descend(from, to *K, fn func(K, V) bool, compare func(K, K) int) bool {
+	i := len(n.items)
+	if to != nil {
+		i, _ = n.find(*to, compare)
+	}
+
+	if !n.leaf() && !n.children[i].descend(from, to, fn, compare) {
+		return false
+	}
+
+	for i--; i >= 0; i-- {
+		item := n.items[i]
+		if from != nil && compare(item.key, *from) < 0 {
+			return false
+		}
+
+		if !fn(item.key, item.value) {
+			return false
+		}
+
+		if !n.leaf() && !n.children[i].descend(from, to, fn, compare) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (n *btreeNode[K, V]) clone() *btreeNode[K, V] {
+	// nolint:exhaustivestruct
+	c := &btreeNode[K, V]{items: slices.Clone(n.items)}
+
+	if !n.leaf() {
+		c.children = make([]*btreeNode[K, V], len(n.children))
+		for i, child := range n.children {
+			c.children[i] = child.clone()
+		}
+	}
+
+	return c
+}
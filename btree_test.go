@@ -0,0 +1,320 @@
+package lrmap
+
+import (
+	"cmp"
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestBTreeRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	tree := newBTree[int, int](cmp.Compare[int])
+	ref := make(map[int]int)
+
+	for i := 0; i < 20000; i++ {
+		k := rnd.Intn(2000)
+
+		if rnd.Intn(3) == 0 {
+			tree.Delete(k)
+			delete(ref, k)
+		} else {
+			tree.Set(k, i)
+			ref[k] = i
+		}
+	}
+
+	if tree.Len() != len(ref) {
+		t.Fatalf("Len(), want %d, got %d", len(ref), tree.Len())
+	}
+
+	for k, v := range ref {
+		if _v, ok := tree.Get(k); !ok || _v != v {
+			t.Errorf("Get(%d), want (%d, true), got (%d, %t)", k, v, _v, ok)
+		}
+	}
+
+	var keys []int
+
+	tree.Iterate(func(k int, _ int) bool {
+		keys = append(keys, k)
+
+		return true
+	})
+
+	if !slices.IsSorted(keys) || len(keys) != len(ref) {
+		t.Errorf("Iterate: keys not sorted or incomplete (%d of %d)", len(keys), len(ref))
+	}
+
+	var desc []int
+
+	tree.IterateDesc(func(k int, _ int) bool {
+		desc = append(desc, k)
+
+		return true
+	})
+
+	slices.Reverse(desc)
+
+	if !slices.Equal(desc, keys) {
+		t.Errorf("IterateDesc: keys are not the reversed keys of Iterate")
+	}
+
+	for i := 0; i < 100; i++ {
+		from, to := rnd.Intn(2100)-50, rnd.Intn(2100)-50
+
+		var want, got []int
+
+		for _, k := range slices.Backward(keys) {
+			if k >= from && k < to {
+				want = append(want, k)
+			}
+		}
+
+		tree.RangeDesc(from, to, func(k int, _ int) bool {
+			got = append(got, k)
+
+			return true
+		})
+
+		if !slices.Equal(got, want) {
+			t.Fatalf("RangeDesc(%d, %d), want %v, got %v", from, to, want, got)
+		}
+	}
+
+	checkBTreeNode(t, tree.root, true)
+
+	clone := tree.Clone()
+
+	for k := range ref {
+		tree.Delete(k)
+	}
+
+	if tree.Len() != 0 || tree.root != nil {
+		t.Errorf("after deleting all keys: Len() = %d, root = %v", tree.Len(), tree.root)
+	}
+
+	if clone.Len() != len(ref) {
+		t.Errorf("clone Len(), want %d, got %d", len(ref), clone.Len())
+	}
+}
+
+func checkBTreeNode[K comparable, V any](t *testing.T, n *btreeNode[K, V], root bool) int {
+	t.Helper()
+
+	if n == nil {
+		return 0
+	}
+
+	if len(n.items) > btreeMaxItems || (!root && len(n.items) < btreeMinItems) {
+		t.Errorf("node with %d items violates bounds", len(n.items))
+	}
+
+	if n.leaf() {
+		return 1
+	}
+
+	if len(n.children) != len(n.items)+1 {
+		t.Errorf("node with %d items has %d children", len(n.items), len(n.children))
+	}
+
+	depth := checkBTreeNode(t, n.children[0], false)
+	for _, child := range n.children[1:] {
+		if d := checkBTreeNode(t, child, false); d != depth {
+			t.Errorf("unbalanced tree: depths %d and %d", depth, d)
+		}
+	}
+
+	return depth + 1
+}
+
+func TestReadHandlerRange(t *testing.T) {
+	lrm := New(WithOrderedArena[int, int](cmp.Compare[int]))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	for i := 0; i < 1000; i++ {
+		lrm.Set(i, i)
+	}
+
+	lrm.Delete(105)
+	lrm.Commit()
+
+	rh.Enter()
+
+	var keys []int
+
+	rh.Range(100, 110, func(k int, _ int) bool {
+		keys = append(keys, k)
+
+		return true
+	})
+
+	if want := []int{100, 101, 102, 103, 104, 106, 107, 108, 109}; !slices.Equal(keys, want) {
+		t.Errorf("Range(100, 110), want %v, got %v", want, keys)
+	}
+
+	rh.Leave()
+}
+
+func TestReadHandlerRangeDesc(t *testing.T) {
+	lrm := New(WithOrderedArena[int, int](cmp.Compare[int]))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	for i := 0; i < 1000; i++ {
+		lrm.Set(i, i)
+	}
+
+	lrm.Delete(105)
+	lrm.Commit()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	var keys []int
+
+	rh.RangeDesc(100, 110, func(k int, _ int) bool {
+		keys = append(keys, k)
+
+		return len(keys) < 5
+	})
+
+	if want := []int{109, 108, 107, 106, 104}; !slices.Equal(keys, want) {
+		t.Errorf("RangeDesc(100, 110), want %v, got %v", want, keys)
+	}
+
+	keys = keys[:0]
+
+	rh.IterateDesc(func(k int, _ int) bool {
+		keys = append(keys, k)
+
+		return len(keys) < 3
+	})
+
+	if want := []int{999, 998, 997}; !slices.Equal(keys, want) {
+		t.Errorf("IterateDesc(), want %v, got %v", want, keys)
+	}
+}
+
+func TestReadHandlerRangeUnordered(t *testing.T) {
+	lrm := New[int, int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Range() on an unordered arena did not panic")
+		}
+	}()
+
+	rh.Range(0, 1, func(int, int) bool { return true })
+}
+
+func TestBTreeLoad(t *testing.T) {
+	sizes := []int{0, 1, btreeMaxItems, btreeMaxItems + 1, 2*btreeMaxItems + 1, 1000, 32*32 - 1, 32 * 32, 40000}
+
+	for _, n := range sizes {
+		items := make([]btreeItem[int, int], n)
+		for i := range items {
+			items[i] = btreeItem[int, int]{key: 2 * i, value: i}
+		}
+
+		tree := newBTree[int, int](cmp.Compare[int])
+		tree.load(items)
+		checkBTreeNode(t, tree.root, true)
+
+		if tree.Len() != n {
+			t.Errorf("%d items: Len() = %d", n, tree.Len())
+		}
+
+		var keys []int
+
+		tree.Iterate(func(k, _ int) bool {
+			keys = append(keys, k)
+
+			return true
+		})
+
+		if len(keys) != n || !slices.IsSorted(keys) {
+			t.Errorf("%d items: Iterate() yields %d keys, sorted: %t", n, len(keys), slices.IsSorted(keys))
+		}
+
+		// the loaded tree must take further writes
+		tree.Set(1, 1)
+		tree.Delete(0)
+
+		if v, ok := tree.Get(1); !ok || v != 1 || tree.Contains(0) {
+			t.Errorf("%d items: writes after load are lost", n)
+		}
+
+		checkBTreeNode(t, tree.root, true)
+	}
+}
+
+func TestLoadSorted(t *testing.T) {
+	entries := make([]Entry[int, int], 5000)
+	for i := range entries {
+		entries[i] = Entry[int, int]{Key: i, Value: i}
+	}
+
+	var committed []Op[int, int]
+
+	lrm := New(
+		WithOrderedArena[int, int](cmp.Compare[int]),
+		WithValidator(func(k, _ int) error {
+			if k == 42 {
+				return errors.New("no 42")
+			}
+
+			return nil
+		}),
+		WithPreCommit(func(pending []Op[int, int]) error {
+			committed = append(committed, pending...)
+
+			return nil
+		}),
+	)
+
+	n, err := lrm.LoadSorted(entries)
+	if err != nil || n != len(entries)-1 {
+		t.Fatalf("LoadSorted(), want %d entries, got %d, %v", len(entries)-1, n, err)
+	}
+
+	if len(committed) != n {
+		t.Errorf("pre-commit hook saw %d operations, want %d", len(committed), n)
+	}
+
+	// both arenas must hold the entries
+	for i := 0; i < 2; i++ {
+		rh := lrm.NewReadHandler()
+		rh.Enter()
+
+		if rh.Len() != n || rh.Contains(42) || rh.Get(4999) != 4999 {
+			t.Errorf("generation %d: Len() = %d, Contains(42) = %t", i+1, rh.Len(), rh.Contains(42))
+		}
+
+		rh.Close()
+		lrm.Commit()
+	}
+
+	checkBTreeNode(t, lrm.left.data.(*btree[int, int]).root, true)
+	checkBTreeNode(t, lrm.right.data.(*btree[int, int]).root, true)
+
+	// not empty anymore: the entries are set one by one
+	if n, err := lrm.LoadSorted([]Entry[int, int]{{Key: -1, Value: -1}}); err != nil || n != 1 {
+		t.Errorf("LoadSorted() on a filled map, want 1 entry, got %d, %v", n, err)
+	}
+
+	unsorted := New(WithOrderedArena[int, int](cmp.Compare[int]))
+	if _, err := unsorted.LoadSorted([]Entry[int, int]{{Key: 2, Value: 2}, {Key: 1, Value: 1}}); !errors.Is(err, ErrNotSorted) {
+		t.Errorf("LoadSorted() of unsorted entries, want ErrNotSorted, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package lrmap
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+)
+
+// ErrOverBudget is returned by TrySet for entries that do not fit into the memory budget.
+var ErrOverBudget = errors.New("memory budget exceeded")
+
+type budget[K comparable, V any] struct {
+	limit uint64
+	used  uint64
+	evict func() (K, bool)
+}
+
+// WithMemoryBudget bounds the memory the entries of the map may use to limit bytes, so that a
+// runaway producer cannot exhaust the memory of the process through the map.  An entry counts
+// with the inline sizes of its key and value in each arena, plus the memory reported by the
+// sizer once (see WithSizer).
+//
+// If an entry set by TrySet (or Set) does not fit, evict is asked for keys to delete until it
+// fits.  If evict is nil or reports no key, the entry is rejected with ErrOverBudget.  evict is
+// called with the writer lock held and must not call into the map.  Other writes, e.g. by Add
+// or ApplyOps, count against the budget, but are not rejected.
+func WithMemoryBudget[K comparable, V any](limit uint64, evict func() (K, bool)) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.budget = budget[K, V]{limit: limit, used: 0, evict: evict}
+	}
+}
+
+// MemoryUsed returns the memory the entries use as counted against the budget (see
+// WithMemoryBudget), or zero for maps without a budget.
+func (m *LRMap[K, V]) MemoryUsed() uint64 {
+	m.lock()
+	defer m.mu.Unlock()
+
+	return m.budget.used
+}
+
+// admit makes room for setting key to value, evicting entries if need be.  The caller must
+// hold m.mu.
+func (m *LRMap[K, V]) admit(key K, value V) error {
+	if m.budget.limit == 0 {
+		return nil
+	}
+
+	m.syncKey(key)
+
+	for {
+		used := m.budget.used
+		if old, ok := m.writeMap.Load().data.Get(key); ok {
+			used -= m.entryCost(key, old)
+		}
+
+		if used+m.entryCost(key, value) <= m.budget.limit {
+			return nil
+		}
+
+		victim, ok := m.evictVictim()
+		if !ok {
+			return fmt.Errorf("%w: %d of %d bytes in use", ErrOverBudget, m.budget.used, m.budget.limit)
+		}
+
+		before := m.budget.used
+		m.delete(victim)
+
+		if m.budget.used == before {
+			return fmt.Errorf("%w: evicting %v has not freed memory", ErrOverBudget, victim)
+		}
+	}
+}
+
+func (m *LRMap[K, V]) evictVictim() (K, bool) {
+	if m.budget.evict == nil {
+		var zero K
+
+		return zero, false
+	}
+
+	return m.budget.evict()
+}
+
+// charge updates the memory used by the entries for setting (or, unless set, deleting) key in
+// data, which must be the write map.  The caller must hold m.mu.
+func (m *LRMap[K, V]) charge(data Arena[K, V], key K, value V, set bool) {
+	if m.budget.limit == 0 {
+		return
+	}
+
+	if old, ok := data.Get(key); ok {
+		m.budget.used -= m.entryCost(key, old)
+	}
+
+	if set {
+		m.budget.used += m.entryCost(key, value)
+	}
+}
+
+func (m *LRMap[K, V]) entryCost(key K, value V) uint64 {
+	return 2*uint64(unsafe.Sizeof(key)+unsafe.Sizeof(value)) + m.size(key, value)
+}
@@ -0,0 +1,80 @@
+package lrmap
+
+import (
+	"errors"
+	"testing"
+	"unsafe"
+)
+
+func TestMemoryBudget(t *testing.T) {
+	var (
+		key   string
+		value []byte
+	)
+
+	inline := 2 * uint64(unsafe.Sizeof(key)+unsafe.Sizeof(value))
+	sizer := func(k string, v []byte) uint64 { return uint64(len(k) + cap(v)) }
+
+	// room for two entries of 100 bytes
+	limit := 2 * (inline + 101)
+
+	lrm := New(WithSizer(sizer), WithMemoryBudget[string, []byte](limit, nil))
+
+	if err := lrm.TrySet("a", make([]byte, 100)); err != nil {
+		t.Fatalf("TrySet(a): %v", err)
+	}
+
+	if err := lrm.TrySet("b", make([]byte, 100)); err != nil {
+		t.Fatalf("TrySet(b): %v", err)
+	}
+
+	if err := lrm.TrySet("c", make([]byte, 1)); !errors.Is(err, ErrOverBudget) {
+		t.Errorf("TrySet(c): want ErrOverBudget, got %v", err)
+	}
+
+	// replacing an entry only needs room for the difference
+	if err := lrm.TrySet("a", make([]byte, 50)); err != nil {
+		t.Errorf("TrySet(a) smaller: %v", err)
+	}
+
+	lrm.Delete("b")
+
+	if got, want := lrm.MemoryUsed(), inline+51; got != want {
+		t.Errorf("MemoryUsed(): want %d, got %d", want, got)
+	}
+}
+
+func TestMemoryBudgetEvict(t *testing.T) {
+	var fifo []int
+
+	lrm := New(WithMemoryBudget[int, int](3*2*16, func() (int, bool) {
+		if len(fifo) == 0 {
+			return 0, false
+		}
+
+		victim := fifo[0]
+		fifo = fifo[1:]
+
+		return victim, true
+	}))
+
+	for i := 0; i < 5; i++ {
+		if err := lrm.TrySet(i, i); err != nil {
+			t.Fatalf("TrySet(%d): %v", i, err)
+		}
+
+		fifo = append(fifo, i)
+	}
+
+	lrm.Commit()
+
+	if got := lrm.Stats().Len; got != 3 {
+		t.Errorf("Len: want 3, got %d", got)
+	}
+
+	for i := 0; i < 2; i++ {
+		if lrm.Contains(i) {
+			t.Errorf("want %d evicted", i)
+		}
+	}
+}
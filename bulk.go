@@ -0,0 +1,157 @@
+package lrmap
+
+import (
+	"errors"
+	"iter"
+)
+
+// ErrNotSorted is returned by LoadSorted for entries that are not in ascending key order.
+var ErrNotSorted = errors.New("entries are not sorted")
+
+// LoadOptions control bulk loading, see LoadFrom.
+type LoadOptions struct {
+	// CommitEvery makes LoadFrom commit after that many entries, so that readers see the
+	// load progress and the redo log stays bounded.  Zero commits once at the end.
+	CommitEvery int
+
+	// SizeHint is the expected number of entries.  If the write map is an empty MapArena, it
+	// is pre-sized accordingly.
+	SizeHint int
+
+	// Progress, if set, is called after each commit with the number of entries loaded so far.
+	Progress func(loaded int)
+}
+
+// LoadFrom sets all entries of seq and commits them, e.g. to hydrate the map from a database
+// cursor.  Entries that a validator rejects are skipped.  It returns the number of entries
+// set.
+func (m *LRMap[K, V]) LoadFrom(seq iter.Seq2[K, V], opts LoadOptions) int {
+	m.presize(opts)
+
+	loaded, pending := 0, 0
+
+	for key, value := range seq {
+		if m.TrySet(key, value) != nil {
+			continue
+		}
+
+		loaded++
+		pending++
+
+		if opts.CommitEvery > 0 && pending >= opts.CommitEvery {
+			m.Commit()
+			pending = 0
+
+			if opts.Progress != nil {
+				opts.Progress(loaded)
+			}
+		}
+	}
+
+	if pending > 0 || opts.CommitEvery <= 0 {
+		m.Commit()
+
+		if opts.Progress != nil {
+			opts.Progress(loaded)
+		}
+	}
+
+	return loaded
+}
+
+// LoadFromChan is like LoadFrom, but reads the entries from ch until it is closed.
+func (m *LRMap[K, V]) LoadFromChan(ch <-chan Entry[K, V], opts LoadOptions) int {
+	return m.LoadFrom(func(yield func(K, V) bool) {
+		for e := range ch {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}, opts)
+}
+
+// LoadSorted sets all entries, which must be in ascending key order without duplicates, and
+// commits them, e.g. to hydrate a map WithOrderedArena from a sorted database export.  Entries
+// that a validator rejects are skipped.  It returns the number of entries set.
+//
+// If the map is empty and has the arena of WithOrderedArena, LoadSorted builds the tree
+// bottom-up in linear time, and the commit copies it to the other arena instead of replaying
+// the entries.  A key normalizer or memory budget, or another arena, makes LoadSorted set the
+// entries one by one; it then does not check their order.
+func (m *LRMap[K, V]) LoadSorted(entries []Entry[K, V]) (int, error) {
+	m.lock()
+
+	if err := m.writable(); err != nil {
+		m.mu.Unlock()
+
+		return 0, err
+	}
+
+	m.syncAll()
+
+	tree, ok := m.writeMap.Load().data.(*btree[K, V])
+	if !ok || tree.Len() > 0 || m.readMap.Load().data.Len() > 0 || len(m.redoLog) > 0 ||
+		m.normalizer != nil || m.budget.limit > 0 {
+		m.mu.Unlock()
+
+		return m.LoadFrom(func(yield func(K, V) bool) {
+			for _, e := range entries {
+				if !yield(e.Key, e.Value) {
+					return
+				}
+			}
+		}, LoadOptions{}), nil // nolint:exhaustivestruct
+	}
+
+	for i := 1; i < len(entries); i++ {
+		if tree.compare(entries[i-1].Key, entries[i].Key) >= 0 {
+			m.mu.Unlock()
+
+			return 0, ErrNotSorted
+		}
+	}
+
+	items := make([]btreeItem[K, V], 0, len(entries))
+
+	for _, e := range entries {
+		if m.validate(e.Key, e.Value) != nil {
+			continue
+		}
+
+		e.Key = m.intern(e.Key)
+		m.written(tree, e.Key, e.Value, true)
+		items = append(items, btreeItem[K, V]{key: e.Key, value: e.Value})
+
+		// the log feeds hooks, persisters and subscribers; the commit does not replay it
+		m.log(operation[K, V]{typ: OpSet, key: e.Key, value: e.Value})
+	}
+
+	tree.load(items)
+	m.rebuildNext = true
+	m.mu.Unlock()
+
+	m.Commit()
+
+	return len(items), nil
+}
+
+func (m *LRMap[K, V]) presize(opts LoadOptions) {
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.writable() != nil {
+		return
+	}
+
+	m.syncAll()
+
+	write := m.writeMap.Load()
+
+	if data, ok := write.data.(MapArena[K, V]); ok && len(data) == 0 && opts.SizeHint > 0 {
+		write.data = make(MapArena[K, V], opts.SizeHint)
+	}
+
+	if n := min(opts.CommitEvery, opts.SizeHint); n > cap(m.redoLog) && len(m.redoLog) == 0 {
+		m.redoLog = make([]operation[K, V], 0, n)
+	}
+}
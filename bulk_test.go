@@ -0,0 +1,59 @@
+package lrmap
+
+import (
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestLoadFrom(t *testing.T) {
+	src := make(map[int]int, 1000)
+	for i := 0; i < 1000; i++ {
+		src[i] = i * i
+	}
+
+	lrm := New[int, int]()
+
+	var progress []int
+
+	n := lrm.LoadFrom(maps.All(src), LoadOptions{
+		CommitEvery: 300,
+		SizeHint:    len(src),
+		Progress:    func(loaded int) { progress = append(progress, loaded) },
+	})
+
+	if n != len(src) {
+		t.Errorf("LoadFrom() = %d, want %d", n, len(src))
+	}
+
+	if want := []int{300, 600, 900, 1000}; !slices.Equal(progress, want) {
+		t.Errorf("progress = %v, want %v", progress, want)
+	}
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if rh.Len() != len(src) || rh.Get(999) != 999*999 {
+		t.Errorf("committed view does not match the source")
+	}
+}
+
+func TestLoadFromChan(t *testing.T) {
+	ch := make(chan Entry[string, int], 2)
+	ch <- Entry[string, int]{Key: "a", Value: 1}
+	ch <- Entry[string, int]{Key: "b", Value: 2}
+	close(ch)
+
+	lrm := New[string, int]()
+
+	if n := lrm.LoadFromChan(ch, LoadOptions{}); n != 2 {
+		t.Errorf("LoadFromChan() = %d, want 2", n)
+	}
+
+	if s := lrm.Stats(); s.CommittedLen != 2 || s.PendingOps != 0 {
+		t.Errorf("entries have not been committed: %+v", s)
+	}
+}
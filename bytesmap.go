@@ -0,0 +1,79 @@
+package lrmap
+
+import "unsafe"
+
+type (
+	// BytesMap is an LRMap with []byte keys, e.g. for keys parsed from network protocols.  It
+	// stores keys as strings: writes copy the key, so the caller may reuse its buffer, while
+	// lookups convert the key without copying or allocating.
+	BytesMap[V any] struct {
+		lrmap *LRMap[string, V]
+	}
+
+	BytesReadHandler[V any] struct {
+		rh *ReadHandler[string, V]
+	}
+)
+
+func NewBytes[V any](opts ...Option[string, V]) *BytesMap[V] {
+	return &BytesMap[V]{lrmap: New(opts...)}
+}
+
+func (m *BytesMap[V]) Set(key []byte, value V) { m.lrmap.Set(string(key), value) }
+func (m *BytesMap[V]) Delete(key []byte)       { m.lrmap.Delete(string(key)) }
+func (m *BytesMap[V]) Commit()                 { m.lrmap.Commit() }
+
+func (m *BytesMap[V]) Get(key []byte) V {
+	value, _ := m.GetOK(key)
+
+	return value
+}
+
+func (m *BytesMap[V]) GetOK(key []byte) (V, bool) { return m.lrmap.GetOK(lookupKey(key)) }
+
+func (m *BytesMap[V]) NewReadHandler() *BytesReadHandler[V] {
+	return &BytesReadHandler[V]{rh: m.lrmap.NewReadHandler()}
+}
+
+func (rh *BytesReadHandler[V]) Enter()   { rh.rh.Enter() }
+func (rh *BytesReadHandler[V]) Leave()   { rh.rh.Leave() }
+func (rh *BytesReadHandler[V]) Close()   { rh.rh.Close() }
+func (rh *BytesReadHandler[V]) Recycle() { rh.rh.Recycle() }
+func (rh *BytesReadHandler[V]) Len() int { return rh.rh.Len() }
+
+func (rh *BytesReadHandler[V]) Get(key []byte) V {
+	value, _ := rh.GetOK(key)
+
+	return value
+}
+
+func (rh *BytesReadHandler[V]) GetOK(key []byte) (V, bool) {
+	return rh.rh.GetOK(rh.key(key))
+}
+
+func (rh *BytesReadHandler[V]) Contains(key []byte) bool {
+	return rh.rh.Contains(rh.key(key))
+}
+
+// Iterate calls fn for all entries until fn returns false.  The key is backed by the map and
+// must not be modified.
+func (rh *BytesReadHandler[V]) Iterate(fn func(key []byte, value V) bool) {
+	rh.rh.Iterate(func(key string, value V) bool {
+		return fn(unsafe.Slice(unsafe.StringData(key), len(key)), value)
+	})
+}
+
+// key converts key for a lookup.  Sampling hot keys retains the key, so then it is copied.
+func (rh *BytesReadHandler[V]) key(key []byte) string {
+	if rh.rh.inner.lrmap.hotKeys != nil {
+		return string(key)
+	}
+
+	return lookupKey(key)
+}
+
+// lookupKey converts key to a string without copying.  The string must not outlive the call it
+// is passed to, since the caller may modify key afterwards.
+func lookupKey(key []byte) string {
+	return unsafe.String(unsafe.SliceData(key), len(key))
+}
@@ -0,0 +1,72 @@
+package lrmap
+
+import "testing"
+
+func TestBytesMap(t *testing.T) {
+	lrm := NewBytes[int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	key := []byte("one")
+	lrm.Set(key, 1)
+	copy(key, "two")
+	lrm.Set(key, 2)
+	lrm.Set([]byte("three"), 3)
+	lrm.Delete([]byte("three"))
+
+	if v, ok := lrm.GetOK([]byte("one")); !ok || v != 1 {
+		t.Errorf("writer GetOK(one), want (1, true), got (%d, %t)", v, ok)
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if n := rh.Len(); n != 2 {
+		t.Errorf("Len(), want 2, got %d", n)
+	}
+
+	if v := rh.Get([]byte("two")); v != 2 {
+		t.Errorf("Get(two), want 2, got %d", v)
+	}
+
+	if rh.Contains([]byte("three")) {
+		t.Error("Contains(three) after Delete")
+	}
+
+	sum := 0
+
+	rh.Iterate(func(key []byte, value int) bool {
+		if string(key) != "one" && string(key) != "two" {
+			t.Errorf("unexpected key %q", key)
+		}
+
+		sum += value
+
+		return true
+	})
+
+	if sum != 3 {
+		t.Errorf("sum of values, want 3, got %d", sum)
+	}
+}
+
+func TestBytesMapLookupAllocs(t *testing.T) {
+	lrm := NewBytes[int]()
+	lrm.Set([]byte("key"), 1)
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	key := []byte("key")
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if n := testing.AllocsPerRun(100, func() { _ = rh.Get(key) }); n != 0 {
+		t.Errorf("Get allocates %v times", n)
+	}
+}
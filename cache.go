@@ -0,0 +1,110 @@
+package lrmap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache is an eventually consistent map that hides the left-right protocol.  Set and Delete are
+// applied to the write side right away and published in batches: as soon as maxBatch writes
+// are pending, or at most interval after the first pending write.  Get reads the published
+// view.
+type Cache[K comparable, V any] struct {
+	lrmap    *LRMap[K, V]
+	maxBatch int64
+	pending  atomic.Int64
+	stop     chan struct{}
+	done     sync.WaitGroup
+	closed   sync.Once
+}
+
+// NewCache returns a Cache that publishes after maxBatch writes (0 means no limit) or after
+// interval (0 means never).  With neither, writes are published by Flush only.  The Cache must
+// be closed to stop publishing.
+func NewCache[K comparable, V any](maxBatch int, interval time.Duration, opts ...Option[K, V]) *Cache[K, V] {
+	// nolint:exhaustivestruct
+	c := &Cache[K, V]{
+		lrmap:    New(opts...),
+		maxBatch: int64(maxBatch),
+		stop:     make(chan struct{}),
+	}
+
+	if interval > 0 {
+		c.done.Add(1)
+
+		go c.publish(interval)
+	}
+
+	return c
+}
+
+func (c *Cache[K, V]) Get(key K) V {
+	value, _ := c.GetOK(key)
+
+	return value
+}
+
+func (c *Cache[K, V]) GetOK(key K) (V, bool) {
+	rh := c.lrmap.NewReadHandler()
+	defer rh.Recycle()
+
+	if rh.TryEnter() != nil {
+		var zero V
+
+		return zero, false
+	}
+
+	defer rh.Leave()
+
+	return rh.GetOK(key)
+}
+
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.lrmap.Set(key, value)
+	c.wrote()
+}
+
+func (c *Cache[K, V]) Delete(key K) {
+	c.lrmap.Delete(key)
+	c.wrote()
+}
+
+// Flush publishes all pending writes.
+func (c *Cache[K, V]) Flush() {
+	c.pending.Store(0)
+	c.lrmap.Commit()
+}
+
+// Close stops publishing by interval, publishes all pending writes and closes the map.  It
+// returns the error of closing the map, or ErrClosed if the Cache has been closed before.
+func (c *Cache[K, V]) Close() error {
+	err := ErrClosed
+
+	c.closed.Do(func() {
+		close(c.stop)
+		c.done.Wait()
+		c.Flush()
+
+		err = c.lrmap.Close()
+	})
+
+	return err
+}
+
+func (c *Cache[K, V]) wrote() {
+	if n := c.pending.Add(1); c.maxBatch > 0 && n >= c.maxBatch {
+		c.Flush()
+	}
+}
+
+// publish flushes pending writes every interval of the map's clock (see WithClock).
+func (c *Cache[K, V]) publish(interval time.Duration) {
+	defer c.done.Done()
+
+	every(c.lrmap.clock, interval, c.stop, func() {
+		if c.pending.Load() > 0 {
+			c.Flush()
+		}
+	})
+}
@@ -0,0 +1,507 @@
+// Package cache implements a read-optimized cache on top of lrmap.  Reads take no locks and
+// never wait for writers; writes are published right away, so each write pays for a commit.
+// The cache supports expiry by TTL, bounds on the number of entries and their total weight, and
+// a loader for misses.
+package cache
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/jwkohnen/lrmap"
+)
+
+var (
+	// ErrNotFound is returned by Get for missing keys if the cache has no loader.
+	ErrNotFound = errors.New("cache: key not found")
+
+	// ErrLoaderPanicked is returned to the callers that wait for a load whose loader has
+	// panicked.  The panic goes on in the goroutine that ran the loader.
+	ErrLoaderPanicked = errors.New("cache: loader panicked")
+)
+
+type (
+	Cache[K comparable, V any] struct {
+		lrmap      *lrmap.LRMap[K, *entry[V]]
+		ttl        time.Duration
+		maxEntries int
+		maxWeight  int64
+		weigher    func(K, V) int64
+		loader     func(context.Context, K) (V, error)
+		onEvict    func(K, V, EvictReason)
+		now        func() time.Time
+		clock      lrmap.Clock
+
+		// mu serializes writers, which keeps len and weight exact, and protects loads.
+		mu      sync.Mutex
+		len     int
+		weight  int64
+		loads   map[K]*loadCall[V]
+		evicted []evicted[K, V]
+
+		// sweepAt is when evict removes expired entries next, even within the bounds (Unix
+		// nanoseconds, 0 means no entry expires).
+		sweepAt int64
+
+		hits, misses, loadCount, loadErrors, evictions, expirations atomic.Uint64
+	}
+
+	Option[K comparable, V any] func(*Cache[K, V])
+
+	// EvictReason tells why an entry has been removed from the cache.
+	EvictReason int
+
+	Stats struct {
+		Hits, Misses           uint64
+		Loads, LoadErrors      uint64
+		Evictions, Expirations uint64
+	}
+
+	// entry is shared by both arenas of the map, so readers may record accesses in place.
+	entry[V any] struct {
+		value      V
+		weight     int64
+		expires    int64 // Unix nanoseconds, 0 means never
+		lastAccess atomic.Int64
+	}
+
+	// evicted is an entry that has been removed, but not yet published without it.
+	evicted[K comparable, V any] struct {
+		key    K
+		value  V
+		reason EvictReason
+	}
+
+	loadCall[V any] struct {
+		done  chan struct{}
+		value V
+		err   error
+	}
+)
+
+// WithTTL makes entries expire ttl after they have been set.
+func WithTTL[K comparable, V any](ttl time.Duration) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.ttl = ttl
+	}
+}
+
+// WithClock replaces the system clock, both for expiry by TTL and for the map behind the
+// cache (see lrmap.WithClock).
+func WithClock[K comparable, V any](clock lrmap.Clock) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.clock = clock
+		c.now = clock.Now
+	}
+}
+
+// WithMaxEntries bounds the cache to n entries.  If a write exceeds the bound, the least
+// recently used entries are evicted in a batch, down to 15/16 of n, so that the cost of
+// finding them is spread over many writes.
+func WithMaxEntries[K comparable, V any](n int) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.maxEntries = n
+	}
+}
+
+const (
+	// Expired entries have outlived their TTL.
+	Expired EvictReason = iota + 1
+
+	// Evicted entries have been the least recently used when the cache exceeded its bound.
+	Evicted
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case Expired:
+		return "expired"
+	case Evicted:
+		return "evicted"
+	default:
+		return "unknown"
+	}
+}
+
+// WithMaxWeight bounds the total weight of the entries to limit, where weigher tells the weight
+// of an entry, e.g. the size of a cached blob in bytes.  Like WithMaxEntries, exceeding the
+// bound evicts the least recently used entries in a batch, down to 15/16 of limit.  Both bounds
+// may be combined.
+func WithMaxWeight[K comparable, V any](limit int64, weigher func(K, V) int64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.maxWeight = limit
+		c.weigher = weigher
+	}
+}
+
+// WithOnEvict registers fn to be called for each entry that expiry or eviction removes, e.g. to
+// release resources held by the value.  fn is called after the removal has been published and
+// all readers that might have seen the entry in the cache have moved on, with the writer lock
+// of the cache held, so it must not call into the cache.
+func WithOnEvict[K comparable, V any](fn func(key K, value V, reason EvictReason)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.onEvict = fn
+	}
+}
+
+// WithLoader makes Get call loader for missing keys and cache its result.  Concurrent misses of
+// the same key share a single call of loader.
+func WithLoader[K comparable, V any](loader func(context.Context, K) (V, error)) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.loader = loader
+	}
+}
+
+func New[K comparable, V any](opts ...Option[K, V]) *Cache[K, V] {
+	// nolint:exhaustivestruct
+	c := &Cache[K, V]{
+		now:   time.Now,
+		clock: lrmap.SystemClock(),
+		loads: make(map[K]*loadCall[V]),
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.lrmap = lrmap.New(lrmap.WithClock[K, *entry[V]](c.clock))
+
+	return c
+}
+
+// Get returns the value of key, loading it if the cache has a loader.
+func (c *Cache[K, V]) Get(ctx context.Context, key K) (V, error) {
+	e, ok, err := c.lookup(key)
+	if err != nil {
+		var zero V
+
+		return zero, err
+	}
+
+	if ok {
+		c.hits.Add(1)
+
+		return e.value, nil
+	}
+
+	c.misses.Add(1)
+
+	if c.loader == nil {
+		var zero V
+
+		return zero, ErrNotFound
+	}
+
+	return c.load(ctx, key)
+}
+
+func (c *Cache[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(key, value)
+}
+
+func (c *Cache[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if e, ok := c.lrmap.Pop(key); ok {
+		c.len--
+		c.weight -= e.weight
+		c.lrmap.Commit()
+	}
+}
+
+// Close closes the underlying map, which releases its entries.  Get fails with
+// lrmap.ErrClosed afterwards, and writes are dropped.
+func (c *Cache[K, V]) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.len, c.weight = 0, 0
+
+	return c.lrmap.Close()
+}
+
+// Len returns the number of entries, including expired ones that have not been removed yet.
+func (c *Cache[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.len
+}
+
+// Weight returns the total weight of the entries (see WithMaxWeight).
+func (c *Cache[K, V]) Weight() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.weight
+}
+
+func (c *Cache[K, V]) Stats() Stats {
+	return Stats{
+		Hits:        c.hits.Load(),
+		Misses:      c.misses.Load(),
+		Loads:       c.loadCount.Load(),
+		LoadErrors:  c.loadErrors.Load(),
+		Evictions:   c.evictions.Load(),
+		Expirations: c.expirations.Load(),
+	}
+}
+
+// lookup returns the live entry of key from the committed view and records the access.  It
+// fails with lrmap.ErrClosed if the map has been closed.
+func (c *Cache[K, V]) lookup(key K) (*entry[V], bool, error) {
+	rh := c.lrmap.NewReadHandler()
+	defer rh.Recycle()
+
+	if err := rh.TryEnter(); err != nil {
+		return nil, false, err
+	}
+
+	e, ok := rh.GetOK(key)
+	rh.Leave()
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	now := c.now().UnixNano()
+	if e.expired(now) {
+		return nil, false, nil
+	}
+
+	e.lastAccess.Store(now)
+
+	return e, true, nil
+}
+
+func (c *Cache[K, V]) load(ctx context.Context, key K) (V, error) {
+	c.mu.Lock()
+
+	call, loading := c.loads[key]
+	if !loading {
+		// A load that finished since the lookup has already been published.
+		if e, ok, err := c.lookup(key); err != nil || ok {
+			c.mu.Unlock()
+
+			if err != nil {
+				var zero V
+
+				return zero, err
+			}
+
+			return e.value, nil
+		}
+
+		call = &loadCall[V]{done: make(chan struct{})} // nolint:exhaustivestruct
+		c.loads[key] = call
+	}
+
+	c.mu.Unlock()
+
+	if !loading {
+		c.runLoader(ctx, key, call)
+	}
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero V
+
+		return zero, ctx.Err()
+	}
+}
+
+// runLoader calls the loader, stores its value and completes call.
+func (c *Cache[K, V]) runLoader(ctx context.Context, key K, call *loadCall[V]) {
+	c.loadCount.Add(1)
+
+	panicked := true
+
+	defer func() {
+		c.mu.Lock()
+
+		if panicked {
+			call.err = ErrLoaderPanicked
+			c.loadErrors.Add(1)
+		}
+
+		delete(c.loads, key)
+		c.mu.Unlock()
+
+		close(call.done)
+	}()
+
+	call.value, call.err = c.loader(ctx, key)
+	panicked = false
+
+	c.mu.Lock()
+
+	if call.err == nil {
+		c.set(key, call.value)
+	} else {
+		c.loadErrors.Add(1)
+	}
+
+	c.mu.Unlock()
+}
+
+// set stores and publishes an entry.  The caller must hold c.mu.
+func (c *Cache[K, V]) set(key K, value V) {
+	now := c.now().UnixNano()
+
+	e := &entry[V]{value: value} // nolint:exhaustivestruct
+	e.lastAccess.Store(now)
+
+	if c.ttl > 0 {
+		e.expires = now + int64(c.ttl)
+
+		if c.sweepAt == 0 {
+			c.sweepAt = e.expires
+		}
+	}
+
+	if c.weigher != nil {
+		e.weight = c.weigher(key, value)
+	}
+
+	old, ok := c.lrmap.GetOK(key)
+
+	// only fails once the cache has been closed
+	if c.lrmap.TrySet(key, e) != nil {
+		return
+	}
+
+	if ok {
+		c.weight -= old.weight
+	} else {
+		c.len++
+	}
+
+	c.weight += e.weight
+
+	c.evict(now)
+	c.lrmap.Commit()
+
+	// The commit has waited for the readers of the arena that still held the removed entries.
+	for _, ev := range c.evicted {
+		c.onEvict(ev.key, ev.value, ev.reason)
+	}
+
+	clear(c.evicted)
+	c.evicted = c.evicted[:0]
+}
+
+// evict removes expired entries and the least recently used ones if the cache exceeds its
+// bounds.  Within the bounds, expired entries are swept at most every 1/16 of the TTL.  The
+// caller must hold c.mu.
+func (c *Cache[K, V]) evict(now int64) {
+	exceeded := (c.maxEntries > 0 && c.len > c.maxEntries) || (c.maxWeight > 0 && c.weight > c.maxWeight)
+	if !exceeded && (c.sweepAt == 0 || now < c.sweepAt) {
+		return
+	}
+
+	type access struct {
+		at     int64
+		weight int64
+	}
+
+	var (
+		accesses []access
+		next     int64
+	)
+
+	expired := c.lrmap.DeleteFunc(func(key K, e *entry[V]) bool {
+		if e.expired(now) {
+			c.removed(key, e, Expired)
+			c.weight -= e.weight
+
+			return true
+		}
+
+		if e.expires != 0 && (next == 0 || e.expires < next) {
+			next = e.expires
+		}
+
+		accesses = append(accesses, access{at: e.lastAccess.Load(), weight: e.weight})
+
+		return false
+	})
+
+	c.len -= expired
+	c.expirations.Add(uint64(expired))
+
+	c.sweepAt = next
+	if next != 0 {
+		c.sweepAt = max(next, now+int64(c.ttl/16))
+	}
+
+	if !exceeded {
+		return
+	}
+
+	excess, excessWeight := 0, int64(0)
+	if c.maxEntries > 0 {
+		excess = c.len - (c.maxEntries - c.maxEntries/16)
+	}
+
+	if c.maxWeight > 0 {
+		excessWeight = c.weight - (c.maxWeight - c.maxWeight/16)
+	}
+
+	if excess <= 0 && excessWeight <= 0 {
+		return
+	}
+
+	slices.SortFunc(accesses, func(a, b access) int { return cmp.Compare(a.at, b.at) })
+
+	// Find the number of least recently used entries to evict to meet both bounds, and the
+	// last access of the most recently used of them.
+	n := 0
+	for n < len(accesses) && (excess > n || excessWeight > 0) {
+		excessWeight -= accesses[n].weight
+		n++
+	}
+
+	if n == 0 {
+		return
+	}
+
+	cutoff := accesses[n-1].at
+
+	evicted := 0
+	c.lrmap.DeleteFunc(func(key K, e *entry[V]) bool {
+		if evicted < n && e.lastAccess.Load() <= cutoff {
+			evicted++
+			c.removed(key, e, Evicted)
+			c.weight -= e.weight
+
+			return true
+		}
+
+		return false
+	})
+
+	c.len -= evicted
+	c.evictions.Add(uint64(evicted))
+}
+
+// removed records a removed entry for the eviction callback.  The caller must hold c.mu.
+func (c *Cache[K, V]) removed(key K, e *entry[V], reason EvictReason) {
+	if c.onEvict != nil {
+		c.evicted = append(c.evicted, evicted[K, V]{key: key, value: e.value, reason: reason})
+	}
+}
+
+func (e *entry[V]) expired(now int64) bool {
+	return e.expires != 0 && now >= e.expires
+}
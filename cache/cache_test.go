@@ -0,0 +1,329 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jwkohnen/lrmap"
+)
+
+func TestGetSetDelete(t *testing.T) {
+	c := New[string, int]()
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(a) = %v, want ErrNotFound", err)
+	}
+
+	c.Set("a", 1)
+
+	if v, err := c.Get(ctx, "a"); err != nil || v != 1 {
+		t.Errorf("Get(a) = %d, %v, want 1, nil", v, err)
+	}
+
+	c.Delete("a")
+
+	if _, err := c.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(a) after Delete = %v, want ErrNotFound", err)
+	}
+
+	if s, want := c.Stats(), (Stats{Hits: 1, Misses: 2}); s != want {
+		t.Errorf("Stats() = %+v, want %+v", s, want)
+	}
+}
+
+func TestTTL(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	c := New(WithTTL[string, int](time.Minute))
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+
+	now = now.Add(59 * time.Second)
+	if _, err := c.Get(context.Background(), "a"); err != nil {
+		t.Errorf("Get(a) before expiry: %v", err)
+	}
+
+	now = now.Add(time.Second)
+	if _, err := c.Get(context.Background(), "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(a) after expiry = %v, want ErrNotFound", err)
+	}
+
+	// without bounds, the next write removes the expired entry
+	c.Set("b", 2)
+
+	if n := c.Len(); n != 1 {
+		t.Errorf("Len() after expiry = %d, want 1", n)
+	}
+
+	if s := c.Stats(); s.Expirations != 1 {
+		t.Errorf("Stats().Expirations = %d, want 1", s.Expirations)
+	}
+}
+
+func TestMaxEntries(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	c := New(WithMaxEntries[int, int](32))
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 32; i++ {
+		now = now.Add(time.Second)
+		c.Set(i, i)
+	}
+
+	// touch the oldest entry, so that it survives the eviction
+	now = now.Add(time.Second)
+	if _, err := c.Get(context.Background(), 0); err != nil {
+		t.Fatalf("Get(0): %v", err)
+	}
+
+	now = now.Add(time.Second)
+	c.Set(32, 32)
+
+	if n := c.Len(); n != 30 {
+		t.Errorf("Len() = %d, want 30", n)
+	}
+
+	for _, key := range []int{0, 4, 32} {
+		if _, err := c.Get(context.Background(), key); err != nil {
+			t.Errorf("Get(%d): %v", key, err)
+		}
+	}
+
+	for _, key := range []int{1, 2, 3} {
+		if _, err := c.Get(context.Background(), key); err == nil {
+			t.Errorf("Get(%d) succeeded, but should have been evicted", key)
+		}
+	}
+
+	if e := c.Stats().Evictions; e != 3 {
+		t.Errorf("Evictions = %d, want 3", e)
+	}
+}
+
+func TestLoader(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		release = make(chan struct{})
+	)
+
+	c := New(WithLoader(func(_ context.Context, key int) (int, error) {
+		calls.Add(1)
+		<-release
+
+		return key * 2, nil
+	}))
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if v, err := c.Get(context.Background(), 21); err != nil || v != 42 {
+				t.Errorf("Get(21) = %d, %v, want 42, nil", v, err)
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("loader called %d times, want 1", n)
+	}
+
+	if s := c.Stats(); s.Loads != 1 || s.Hits+s.Misses != 10 {
+		t.Errorf("Stats() = %+v", s)
+	}
+}
+
+func TestLoaderPanic(t *testing.T) {
+	var (
+		calls   atomic.Int32
+		release = make(chan struct{})
+	)
+
+	c := New(WithLoader(func(_ context.Context, key int) (int, error) {
+		if calls.Add(1) == 1 {
+			<-release
+			panic("boom")
+		}
+
+		return key * 2, nil
+	}))
+
+	panicked := make(chan any)
+
+	go func() {
+		defer func() { panicked <- recover() }()
+
+		_, _ = c.Get(context.Background(), 21)
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+
+	waited := make(chan error)
+
+	go func() {
+		_, err := c.Get(context.Background(), 21)
+		waited <- err
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	if v := <-panicked; v != "boom" {
+		t.Errorf("loading Get() panicked with %v, want boom", v)
+	}
+
+	if err := <-waited; !errors.Is(err, ErrLoaderPanicked) {
+		t.Errorf("waiting Get() = %v, want ErrLoaderPanicked", err)
+	}
+
+	// the failed load has been unregistered, so the next Get loads again
+	if v, err := c.Get(context.Background(), 21); err != nil || v != 42 {
+		t.Errorf("Get(21) after panic = %d, %v, want 42, nil", v, err)
+	}
+}
+
+func TestOnEvict(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	removed := make(map[int]EvictReason)
+	onEvict := WithOnEvict(func(key, value int, reason EvictReason) {
+		if key != value {
+			t.Errorf("OnEvict(%d, %d): value does not belong to key", key, value)
+		}
+
+		removed[key] = reason
+	})
+
+	c := New(WithTTL[int, int](10*time.Second), onEvict)
+	c.now = func() time.Time { return now }
+
+	c.Set(0, 0)
+	now = now.Add(10 * time.Second)
+	c.Set(1, 1)
+
+	if want := map[int]EvictReason{0: Expired}; !maps.Equal(removed, want) {
+		t.Fatalf("removed: want %v, got %v", want, removed)
+	}
+
+	clear(removed)
+
+	c = New(WithMaxEntries[int, int](4), onEvict)
+	c.now = func() time.Time { return now }
+
+	for i := 0; i <= 4; i++ {
+		now = now.Add(time.Second)
+		c.Set(i, i)
+	}
+
+	if want := map[int]EvictReason{0: Evicted}; !maps.Equal(removed, want) {
+		t.Errorf("removed: want %v, got %v", want, removed)
+	}
+}
+
+func TestMaxWeight(t *testing.T) {
+	now := time.Unix(0, 0)
+
+	c := New(WithMaxWeight(100, func(_ int, blob []byte) int64 { return int64(len(blob)) }))
+	c.now = func() time.Time { return now }
+
+	for i := 0; i < 4; i++ {
+		now = now.Add(time.Second)
+		c.Set(i, make([]byte, 25))
+	}
+
+	if w := c.Weight(); w != 100 {
+		t.Fatalf("Weight() = %d, want 100", w)
+	}
+
+	// replacing an entry accounts for the old weight
+	now = now.Add(time.Second)
+	c.Set(3, make([]byte, 10))
+
+	if w := c.Weight(); w != 85 {
+		t.Fatalf("Weight() = %d, want 85", w)
+	}
+
+	// 85+40 exceeds the limit, so entries 0 and 1 go to get down to 15/16 of it
+	now = now.Add(time.Second)
+	c.Set(4, make([]byte, 40))
+
+	if w, n := c.Weight(), c.Len(); w != 75 || n != 3 {
+		t.Errorf("Weight(), Len() = %d, %d, want 75, 3", w, n)
+	}
+
+	for _, key := range []int{0, 1} {
+		if _, err := c.Get(context.Background(), key); err == nil {
+			t.Errorf("Get(%d) succeeded, but should have been evicted", key)
+		}
+	}
+
+	c.Delete(4)
+
+	if w := c.Weight(); w != 35 {
+		t.Errorf("Weight() after Delete = %d, want 35", w)
+	}
+}
+
+// stepClock is a clock in virtual time, which passes by Sleep only.
+type stepClock struct{ now time.Time }
+
+func (c *stepClock) Now() time.Time                     { return c.now }
+func (c *stepClock) Sleep(d time.Duration)              { c.now = c.now.Add(d) }
+func (c *stepClock) NewTimer(time.Duration) lrmap.Timer { panic("not used by the cache") }
+
+func TestTTLClock(t *testing.T) {
+	clock := &stepClock{now: time.Unix(0, 0)}
+	c := New(WithTTL[string, int](time.Minute), WithClock[string, int](clock))
+
+	c.Set("a", 1)
+
+	clock.Sleep(time.Minute)
+
+	if _, err := c.Get(context.Background(), "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(a) after expiry = %v, want ErrNotFound", err)
+	}
+}
+
+func TestGetAfterClose(t *testing.T) {
+	c := New(WithLoader[string, int](func(context.Context, string) (int, error) {
+		t.Error("loader called on a closed map")
+
+		return 1, nil
+	}))
+	c.Set("a", 1)
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	if err := c.Close(); !errors.Is(err, lrmap.ErrClosed) {
+		t.Errorf("second Close(): want lrmap.ErrClosed, got %v", err)
+	}
+
+	c.Set("c", 3)
+
+	if n := c.Len(); n != 0 {
+		t.Errorf("Len() after Set on a closed cache = %d, want 0", n)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		if _, err := c.Get(context.Background(), key); !errors.Is(err, lrmap.ErrClosed) {
+			t.Errorf("Get(%s) after Close: want lrmap.ErrClosed, got %v", key, err)
+		}
+	}
+}
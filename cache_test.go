@@ -0,0 +1,120 @@
+package lrmap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCacheBatch(t *testing.T) {
+	c := NewCache[int, int](2, 0)
+	defer c.Close()
+
+	c.Set(1, 1)
+
+	if _, ok := c.GetOK(1); ok {
+		t.Errorf("GetOK(1) succeeded before the batch was full")
+	}
+
+	c.Set(2, 2)
+
+	if v := c.Get(1); v != 1 {
+		t.Errorf("Get(1) = %d, want 1", v)
+	}
+}
+
+func TestCacheInterval(t *testing.T) {
+	c := NewCache[int, int](0, time.Millisecond)
+	defer c.Close()
+
+	c.Set(1, 1)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for c.Get(1) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("write has not been published by interval")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type opsRecorder struct {
+	Persister[int, int]
+	ops    []Op[int, int]
+	closed bool
+}
+
+func (r *opsRecorder) AppendOps(batch CommitBatch[int, int]) error {
+	r.ops = append(r.ops, batch.Ops...)
+
+	return nil
+}
+
+func (r *opsRecorder) Close() error { r.closed = true; return nil }
+
+func TestCacheClose(t *testing.T) {
+	p := new(opsRecorder)
+	c := NewCache(0, time.Hour, WithPersister[int, int](p))
+
+	c.Set(1, 1)
+	c.Delete(1)
+	c.Set(2, 2)
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	// the pending writes have been published before the map has been closed
+	want := []Op[int, int]{
+		{Kind: OpSet, Key: 1, Value: 1},
+		{Kind: OpDelete, Key: 1, Value: 0},
+		{Kind: OpSet, Key: 2, Value: 2},
+	}
+
+	if !slices.Equal(p.ops, want) {
+		t.Errorf("published ops = %v, want %v", p.ops, want)
+	}
+
+	if !p.closed {
+		t.Error("Close() has not closed the map")
+	}
+
+	if err := c.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("second Close(): want ErrClosed, got %v", err)
+	}
+}
+
+func TestCacheIntervalClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)} // nolint:exhaustivestruct
+	c := NewCache(0, time.Second, WithClock[int, int](clock))
+
+	c.Set(1, 1)
+
+	// wait for the publisher to arm its timer, then let the interval pass in virtual time
+	for clock.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	if _, ok := c.GetOK(1); ok {
+		t.Fatal("write has been published before the interval")
+	}
+
+	clock.Advance(time.Second)
+
+	deadline := time.Now().Add(10 * time.Second)
+	for c.Get(1) != 1 {
+		if time.Now().After(deadline) {
+			t.Fatal("write has not been published by interval")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	c.Close()
+
+	if n := clock.Timers(); n != 0 {
+		t.Errorf("Close() has left %d timers", n)
+	}
+}
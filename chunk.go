@@ -0,0 +1,36 @@
+package lrmap
+
+// IterateChunks calls fn with consecutive batches of up to size entries of the live view until
+// fn returns false or all entries have been visited.  All batches but the last hold exactly size
+// entries.  The slice is reused for the next batch, so fn must copy entries that it wants to
+// keep after returning.
+func (rh *ReadHandler[K, V]) IterateChunks(size int, fn func([]Entry[K, V]) bool) {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must call Enter() before iterating")
+	}
+
+	if size <= 0 {
+		panic("illegal use: chunk size must be positive")
+	}
+
+	chunk := make([]Entry[K, V], 0, min(size, rh.inner.live.data.Len()))
+	stopped := false
+
+	rh.inner.live.data.Iterate(rh.inner.counted(func(key K, value V) bool {
+		chunk = append(chunk, Entry[K, V]{Key: key, Value: value})
+		if len(chunk) < size {
+			return true
+		}
+
+		stopped = !fn(chunk)
+		chunk = chunk[:0]
+
+		return !stopped
+	}))
+
+	if !stopped && len(chunk) > 0 {
+		fn(chunk)
+	}
+}
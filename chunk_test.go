@@ -0,0 +1,67 @@
+package lrmap
+
+import "testing"
+
+func TestIterateChunks(t *testing.T) {
+	lrm := New[int, int]()
+	rh := lrm.NewReadHandler()
+
+	for i := 0; i < 10; i++ {
+		lrm.Set(i, -i)
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	var sizes []int
+
+	seen := make(map[int]bool)
+
+	rh.IterateChunks(4, func(chunk []Entry[int, int]) bool {
+		sizes = append(sizes, len(chunk))
+
+		for _, e := range chunk {
+			if e.Value != -e.Key || seen[e.Key] {
+				t.Errorf("bad or duplicate entry %v", e)
+			}
+
+			seen[e.Key] = true
+		}
+
+		return true
+	})
+
+	if len(sizes) != 3 || sizes[0] != 4 || sizes[1] != 4 || sizes[2] != 2 {
+		t.Errorf("chunk sizes, want [4 4 2], got %v", sizes)
+	}
+
+	if len(seen) != 10 {
+		t.Errorf("want 10 entries, got %d", len(seen))
+	}
+
+	calls := 0
+
+	rh.IterateChunks(5, func([]Entry[int, int]) bool {
+		calls++
+
+		return false
+	})
+
+	if calls != 1 {
+		t.Errorf("want iteration to stop after 1 chunk, got %d", calls)
+	}
+
+	calls = 0
+
+	rh.IterateChunks(5, func([]Entry[int, int]) bool {
+		calls++
+
+		return true
+	})
+
+	if calls != 2 {
+		t.Errorf("want exactly 2 chunks of 5, got %d", calls)
+	}
+}
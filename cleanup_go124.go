@@ -0,0 +1,26 @@
+//go:build go1.24 && !tinygo && !wasip1 && !lrmap_nocleanup
+
+package lrmap
+
+import "runtime"
+
+// cleanupsSupported tells whether addCleanup attaches cleanups.
+const cleanupsSupported = true
+
+// cleanup is a handle to a function that runs once the object it has been attached to is
+// unreachable.  Unlike a finalizer it neither resurrects the object nor delays its
+// reclamation by another GC cycle.
+type cleanup struct {
+	c runtime.Cleanup
+}
+
+// addCleanup arranges for fn(arg) to run after ptr has become unreachable.  arg must not
+// reference ptr, or ptr never becomes unreachable.
+func addCleanup[T, S any](ptr *T, fn func(S), arg S) cleanup {
+	return cleanup{c: runtime.AddCleanup(ptr, fn, arg)}
+}
+
+// stopCleanup cancels a cleanup that has been attached to ptr.
+func stopCleanup[T any](_ *T, c cleanup) {
+	c.c.Stop()
+}
@@ -0,0 +1,22 @@
+//go:build !go1.24 && !tinygo && !wasip1 && !lrmap_nocleanup
+
+package lrmap
+
+import "runtime"
+
+const cleanupsSupported = true
+
+// cleanup emulates runtime.Cleanup with a finalizer before Go 1.24.
+type cleanup struct{}
+
+func addCleanup[T, S any](ptr *T, fn func(S), arg S) cleanup {
+	runtime.SetFinalizer(ptr, func(*T) { fn(arg) })
+
+	return cleanup{}
+}
+
+// stopCleanup takes ptr rather than keeping it in the cleanup, since an object that
+// references itself is not guaranteed to be finalized.
+func stopCleanup[T any](ptr *T, _ cleanup) {
+	runtime.SetFinalizer(ptr, nil)
+}
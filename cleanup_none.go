@@ -0,0 +1,14 @@
+//go:build tinygo || wasip1 || lrmap_nocleanup
+
+package lrmap
+
+// TinyGo and WASI do not support finalizers or do not run them reliably, so handlers are not
+// cleaned up when they become unreachable.  New makes every map WithExplicitClose instead; build
+// with the tag lrmap_nocleanup to get the same behavior elsewhere.
+const cleanupsSupported = false
+
+type cleanup struct{}
+
+func addCleanup[T, S any](*T, func(S), S) cleanup { return cleanup{} }
+
+func stopCleanup[T any](*T, cleanup) {}
@@ -0,0 +1,69 @@
+package lrmap
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestLeakedHandlerIsCleanedUp(t *testing.T) {
+	if !cleanupsSupported {
+		t.Skip("handlers are not cleaned up in this build")
+	}
+
+	lrm := New[int, int]()
+
+	func() {
+		rh := lrm.NewReadHandler()
+		rh.Enter()
+	}()
+
+	for i := 0; i < 10 && countSlots(&lrm.readHandlers) > 0; i++ {
+		runtime.GC()
+		runtime.Gosched()
+	}
+
+	if n := countSlots(&lrm.readHandlers); n != 0 {
+		t.Fatalf("%d slots still in use after the handler became unreachable", n)
+	}
+
+	// the leaked handler has been entered, so this would block forever without the cleanup
+	lrm.Set(1, 1)
+	lrm.Commit()
+}
+
+func TestLeakedSharedHandlerIsCleanedUp(t *testing.T) {
+	if !cleanupsSupported {
+		t.Skip("handlers are not cleaned up in this build")
+	}
+
+	lrm := New[int, int]()
+
+	var slot *epochSlot
+
+	func() {
+		s := lrm.NewSharedReadHandler()
+		s.Enter()
+		slot = s.slot
+	}()
+
+	for i := 0; i < 10 && countSlots(&lrm.readHandlers) > 0; i++ {
+		runtime.GC()
+		runtime.Gosched()
+	}
+
+	if n := countSlots(&lrm.readHandlers); n != 0 {
+		t.Fatalf("%d slots still in use after the handler became unreachable", n)
+	}
+
+	// a handler that reused a slot released with an odd epoch would count as left once entered
+	if slot.epoch.Load()%2 == 1 {
+		t.Fatal("slot released while entered")
+	}
+}
+
+func countSlots(r *registry) int {
+	n := 0
+	r.forEach(func(*epochSlot) { n++ })
+
+	return n
+}
@@ -0,0 +1,56 @@
+package lrmap
+
+import "time"
+
+type (
+	// Clock is the source of time of a map: for polling readers during a commit, for the
+	// background commits of a Cache or Registry, and for expiry by TTL in package cache.
+	// Tests may provide a fake clock to run deterministically in virtual time.
+	Clock interface {
+		Now() time.Time
+		Sleep(d time.Duration)
+		NewTimer(d time.Duration) Timer
+	}
+
+	// Timer is a single event of a Clock, like time.Timer.
+	Timer interface {
+		C() <-chan time.Time
+		Stop() bool
+	}
+
+	systemClock struct{}
+
+	systemTimer struct{ t *time.Timer }
+)
+
+// SystemClock returns the clock that maps use by default.
+func SystemClock() Clock { return systemClock{} }
+
+func (systemClock) Now() time.Time                 { return time.Now() }
+func (systemClock) Sleep(d time.Duration)          { time.Sleep(d) }
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{t: time.NewTimer(d)} }
+func (t systemTimer) C() <-chan time.Time          { return t.t.C }
+func (t systemTimer) Stop() bool                   { return t.t.Stop() }
+
+// WithClock replaces the system clock.
+func WithClock[K comparable, V any](clock Clock) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.clock = clock
+	}
+}
+
+// every calls fn every interval of clock until stop is closed.
+func every(clock Clock, interval time.Duration, stop <-chan struct{}, fn func()) {
+	for {
+		timer := clock.NewTimer(interval)
+
+		select {
+		case <-stop:
+			timer.Stop()
+
+			return
+		case <-timer.C():
+			fn()
+		}
+	}
+}
@@ -0,0 +1,32 @@
+//go:build go1.25
+
+package lrmap
+
+import (
+	"testing"
+	"testing/synctest"
+	"time"
+)
+
+func TestCommitWaitSynctest(t *testing.T) {
+	synctest.Test(t, func(t *testing.T) {
+		lrm := New[int, int]()
+
+		rh := lrm.NewReadHandler()
+		defer rh.Close()
+
+		rh.Enter()
+
+		go func() {
+			time.Sleep(3 * time.Second)
+			rh.Leave()
+		}()
+
+		lrm.Commit()
+
+		// the reader leaves during the 5s backoff step after 1.111111s of polling
+		if want := 6111111 * time.Microsecond; lrm.LastWait().Wait != want {
+			t.Errorf("LastWait().Wait = %v, want %v", lrm.LastWait().Wait, want)
+		}
+	})
+}
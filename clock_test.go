@@ -0,0 +1,133 @@
+package lrmap
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type (
+	// fakeClock runs in virtual time, which passes by Sleep and Advance only.  Tests may set
+	// now directly as long as no other goroutine uses the clock.
+	fakeClock struct {
+		mu     sync.Mutex
+		now    time.Time
+		sleep  func(now time.Time)
+		timers []*fakeTimer
+	}
+
+	fakeTimer struct {
+		c    chan time.Time
+		at   time.Time
+		done bool // fired or stopped
+	}
+)
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	now := c.Advance(d)
+
+	if c.sleep != nil {
+		c.sleep(now)
+	}
+}
+
+// Advance moves the clock forward by d, fires the timers that are due, and returns the new
+// time.
+func (c *fakeClock) Advance(d time.Duration) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.now = c.now.Add(d)
+
+	kept := c.timers[:0]
+
+	for _, t := range c.timers {
+		switch {
+		case t.done:
+		case t.at.After(c.now):
+			kept = append(kept, t)
+		default:
+			t.done = true
+			t.c <- c.now
+		}
+	}
+
+	c.timers = kept
+
+	return c.now
+}
+
+// Timers returns the number of timers that are waiting.
+func (c *fakeClock) Timers() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	n := 0
+
+	for _, t := range c.timers {
+		if !t.done {
+			n++
+		}
+	}
+
+	return n
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	t := &fakeTimer{c: make(chan time.Time, 1), at: c.now.Add(d)}
+	c.timers = append(c.timers, t)
+
+	return &fakeTimerHandle{clock: c, t: t}
+}
+
+// fakeTimerHandle guards the timer by the lock of its clock.
+type fakeTimerHandle struct {
+	clock *fakeClock
+	t     *fakeTimer
+}
+
+func (h *fakeTimerHandle) C() <-chan time.Time { return h.t.c }
+
+func (h *fakeTimerHandle) Stop() bool {
+	h.clock.mu.Lock()
+	defer h.clock.mu.Unlock()
+
+	active := !h.t.done
+	h.t.done = true
+
+	return active
+}
+
+func TestWithClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	lrm := New(WithClock[int, int](clock))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+
+	// leave as soon as a second of virtual time has passed
+	clock.sleep = func(now time.Time) {
+		if now.Sub(time.Unix(0, 0)) >= time.Second && rh.inner.entered() {
+			rh.Leave()
+		}
+	}
+
+	lrm.Commit()
+
+	// the backoff sleeps 1µs, 10µs, ..., 1s until the reader has left
+	if want := 1111111 * time.Microsecond; lrm.LastWait().Wait != want {
+		t.Errorf("LastWait().Wait = %v, want %v", lrm.LastWait().Wait, want)
+	}
+}
@@ -0,0 +1,27 @@
+package lrmap
+
+// Cloner is implemented by values that can copy themselves.  If the value type of a map
+// implements it, replay stores clones (see WithValueCopier), unless a copier has been set
+// explicitly.
+type Cloner[V any] interface {
+	Clone() V
+}
+
+// WithCloning asserts that the value type implements Cloner, so that replay clones values.
+// New panics if it does not, which catches a value type losing its Clone method early.
+func WithCloning[K comparable, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.requireClone = true
+	}
+}
+
+// cloner returns a copier that calls Clone, if V implements Cloner.
+func cloner[V any]() func(V) V {
+	var zero V
+
+	if _, ok := any(zero).(Cloner[V]); !ok {
+		return nil
+	}
+
+	return func(v V) V { return any(v).(Cloner[V]).Clone() }
+}
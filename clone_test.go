@@ -0,0 +1,43 @@
+package lrmap
+
+import (
+	"slices"
+	"testing"
+)
+
+type clonable struct {
+	items []int
+}
+
+func (c *clonable) Clone() *clonable {
+	return &clonable{items: slices.Clone(c.items)}
+}
+
+func TestCloner(t *testing.T) {
+	lrm := New(WithCloning[string, *clonable]())
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", &clonable{items: []int{1}})
+	lrm.Commit()
+
+	lrm.Get("a").items[0] = 2
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if v := rh.Get("a"); v.items[0] != 1 {
+		t.Errorf("reader sees a value modified by the writer: %v", v.items)
+	}
+}
+
+func TestClonerRequired(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("WithCloning on a value type without Clone did not panic")
+		}
+	}()
+
+	New(WithCloning[string, []int]())
+}
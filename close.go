@@ -0,0 +1,96 @@
+package lrmap
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrClosed is returned by operations on a map that has been closed.
+var ErrClosed = errors.New("map is closed")
+
+// Close shuts the map down: it rejects further writes with ErrClosed, waits for the read
+// handlers that are currently entered to leave, and makes entering any read handler fail from
+// then on.  It drops both arenas (a frozen map keeps its single arena, since its readers are
+// not tracked), closes the pooled read handlers and all subscriptions, and closes the
+// persister if it is an io.Closer.  Writes that have not been committed are discarded.
+//
+// Close returns the error of closing the persister, or ErrClosed if the map has been closed
+// before.
+func (m *LRMap[K, V]) Close() error {
+	m.lock()
+
+	if m.closed.Load() {
+		m.mu.Unlock()
+
+		return ErrClosed
+	}
+
+	// Readers check the flag after they have entered (see readHandlerInner.tryEnter), so the
+	// readers that are not in this snapshot will not touch the arenas.
+	m.closed.Store(true)
+	_ = m.awaitReaders(context.Background(), m.enteredReaders(), maxWaitDelay, func(enteredReader) {}, nil)
+
+	if !m.frozen.Load() {
+		// A nil map is an empty arena that can still be read by the writer methods.
+		m.left.data = MapArena[K, V](nil)
+		m.right.data = MapArena[K, V](nil)
+		m.left.meta = nil
+		m.right.meta = nil
+		m.committedLen.Store(0)
+	}
+
+	m.async.Store(nil)
+	m.redoLog = nil
+	m.notifyDrained()
+	m.redoIndex = nil
+	m.backlog = nil
+
+	if m.interner != nil {
+		m.interner.keys = nil
+	}
+
+	m.dropHistory()
+
+	subs := make([]*subscription[K, V], 0, len(m.subs))
+	for sub := range m.subs {
+		subs = append(subs, sub)
+	}
+
+	m.mu.Unlock()
+
+	for _, sub := range subs {
+		m.unsubscribe(sub)
+	}
+
+	for {
+		select {
+		case rh := <-m.freeHandlers:
+			stopCleanup(rh, rh.cleanup)
+			rh.inner.close()
+
+			continue
+		default:
+		}
+
+		break
+	}
+
+	if closer, ok := m.persister.(io.Closer); ok {
+		return closer.Close()
+	}
+
+	return nil
+}
+
+// writable returns why the map must not be written anymore, if it must not.
+func (m *LRMap[K, V]) writable() error {
+	switch {
+	case m.closed.Load():
+		return ErrClosed
+	case m.frozen.Load():
+		return ErrFrozen
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,175 @@
+package lrmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type closeRecorder struct {
+	Persister[string, int]
+	closed bool
+}
+
+func (c *closeRecorder) AppendOps(CommitBatch[string, int]) error { return nil }
+func (c *closeRecorder) Close() error                             { c.closed = true; return nil }
+
+func TestClose(t *testing.T) {
+	p := new(closeRecorder)
+	lrm := New(WithPersister[string, int](p), WithExplicitClose[string, int]())
+
+	lrm.Set("a", 1)
+	lrm.Commit()
+
+	ch, _ := lrm.Subscribe()
+
+	pooled := lrm.NewReadHandler()
+	pooled.Recycle()
+
+	rh := lrm.NewReadHandler()
+	rh.Enter()
+
+	closed := make(chan error)
+
+	go func() { closed <- lrm.Close() }()
+
+	select {
+	case err := <-closed:
+		t.Fatalf("Close() returned while a reader was entered: %v", err)
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if got := rh.Get("a"); got != 1 {
+		t.Errorf("Get(a) while closing: want 1, got %d", got)
+	}
+
+	rh.Leave()
+
+	if err := <-closed; err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	if !p.closed {
+		t.Errorf("want persister closed")
+	}
+
+	if _, ok := <-ch; ok {
+		t.Errorf("want subscription closed")
+	}
+
+	if got := countSlots(&lrm.readHandlers); got != 1 {
+		t.Errorf("registered handlers: want 1 (the one still held), got %d", got)
+	}
+
+	if err := lrm.TrySet("b", 2); !errors.Is(err, ErrClosed) {
+		t.Errorf("TrySet(): want ErrClosed, got %v", err)
+	}
+
+	if err := lrm.TryCommit(); !errors.Is(err, ErrClosed) {
+		t.Errorf("TryCommit(): want ErrClosed, got %v", err)
+	}
+
+	if got := lrm.Get("a"); got != 0 {
+		t.Errorf("writer Get(a): want 0 after Close, got %d", got)
+	}
+
+	if err := lrm.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("Close() twice: want ErrClosed, got %v", err)
+	}
+
+	defer func() {
+		err, _ := recover().(error)
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("Enter() after Close: want panic with ErrClosed, got %v", err)
+		}
+
+		if rh.inner.entered() {
+			t.Errorf("want handler not entered after failed Enter()")
+		}
+	}()
+
+	rh.Enter()
+}
+
+func TestEnterContext(t *testing.T) {
+	lrm := New[string, int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	if err := rh.EnterContext(ctx); err != nil {
+		t.Fatalf("EnterContext(): %v", err)
+	}
+
+	rh.Leave()
+	cancel()
+
+	if err := rh.EnterContext(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("EnterContext(canceled): want context.Canceled, got %v", err)
+	}
+
+	if err := lrm.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+
+	if err := rh.TryEnter(); !errors.Is(err, ErrClosed) {
+		t.Errorf("TryEnter() after Close: want ErrClosed, got %v", err)
+	}
+
+	if err := rh.EnterContext(context.Background()); !errors.Is(err, ErrClosed) {
+		t.Errorf("EnterContext() after Close: want ErrClosed, got %v", err)
+	}
+}
+
+func TestUseAfterClose(t *testing.T) {
+	t.Run("GetOrLoad", func(t *testing.T) {
+		lrm := New[string, int]()
+		if err := lrm.Close(); err != nil {
+			t.Fatalf("Close(): %v", err)
+		}
+
+		_, err := lrm.GetOrLoad(context.Background(), "a", func(context.Context, string) (int, error) {
+			t.Error("loader called on a closed map")
+
+			return 1, nil
+		})
+		if !errors.Is(err, ErrClosed) {
+			t.Errorf("GetOrLoad() after Close: want ErrClosed, got %v", err)
+		}
+	})
+
+	t.Run("Cache", func(t *testing.T) {
+		c := NewCache[string, int](1, 0)
+		c.Set("a", 1)
+
+		if err := c.Close(); err != nil {
+			t.Fatalf("Close(): %v", err)
+		}
+
+		if v, ok := c.GetOK("a"); ok || v != 0 {
+			t.Errorf("GetOK(a) after Close = %d, %t, want 0, false", v, ok)
+		}
+	})
+
+	t.Run("SyncMapAdapter", func(t *testing.T) {
+		a := NewSyncMapAdapter[string, int](1)
+		a.Store("a", 1)
+
+		if err := a.lrmap.Close(); err != nil {
+			t.Fatalf("Close(): %v", err)
+		}
+
+		if v, ok := a.Load("a"); ok || v != 0 {
+			t.Errorf("Load(a) after Close = %d, %t, want 0, false", v, ok)
+		}
+
+		a.Range(func(string, int) bool {
+			t.Error("Range() after Close called fn")
+
+			return true
+		})
+	})
+}
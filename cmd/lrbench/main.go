@@ -0,0 +1,271 @@
+// Command lrbench drives a configurable mix of reads and writes against an LRMap, a sync.Map
+// and a map guarded by a sync.RWMutex, and reports throughput, latency percentiles, and
+// allocations for each of them.
+//
+// Usage:
+//
+//	lrbench [-impl lrmap,syncmap,rwmutex] [-duration 5s] [-readers 8] [-writers 1]
+//	        [-keys 100000] [-value-size 64] [-commit-every 100] [-reads-per-enter 1]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"text/tabwriter"
+	"time"
+)
+
+// sampleEvery is the fraction of operations whose latency is measured.  Reading the clock for
+// every operation would dominate the cost of reading from the maps.
+const sampleEvery = 64
+
+type (
+	config struct {
+		impls         []string
+		duration      time.Duration
+		readers       int
+		writers       int
+		keys          int
+		valueSize     int
+		commitEvery   int
+		readsPerEnter int
+	}
+
+	// target is a map under test.  reader returns a read function for a single goroutine,
+	// which reads n keys starting at key.
+	target interface {
+		reader() func(key, n int)
+		writer() func(key int, value []byte)
+	}
+
+	result struct {
+		impl           string
+		reads, writes  uint64
+		readLatencies  []time.Duration
+		writeLatencies []time.Duration
+		allocs         uint64
+		elapsed        time.Duration
+	}
+)
+
+func main() {
+	cfg, err := parseFlags(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	results := make([]result, 0, len(cfg.impls))
+
+	for _, impl := range cfg.impls {
+		results = append(results, run(cfg, impl))
+	}
+
+	report(os.Stdout, results)
+}
+
+func parseFlags(args []string) (config, error) {
+	var (
+		cfg   config
+		impls string
+	)
+
+	fs := flag.NewFlagSet("lrbench", flag.ContinueOnError)
+	fs.StringVar(&impls, "impl", "lrmap,syncmap,rwmutex", "comma separated maps to benchmark")
+	fs.DurationVar(&cfg.duration, "duration", 5*time.Second, "duration per map")
+	fs.IntVar(&cfg.readers, "readers", runtime.GOMAXPROCS(0), "number of reading goroutines")
+	fs.IntVar(&cfg.writers, "writers", 1, "number of writing goroutines")
+	fs.IntVar(&cfg.keys, "keys", 100_000, "number of distinct keys")
+	fs.IntVar(&cfg.valueSize, "value-size", 64, "size of values in bytes")
+	fs.IntVar(&cfg.commitEvery, "commit-every", 100, "writes per commit (lrmap)")
+	fs.IntVar(&cfg.readsPerEnter, "reads-per-enter", 1, "reads per Enter/Leave or lock (lrmap, rwmutex)")
+
+	if err := fs.Parse(args); err != nil {
+		return cfg, err
+	}
+
+	cfg.impls = strings.Split(impls, ",")
+
+	for _, impl := range cfg.impls {
+		if newTarget(impl, cfg) == nil {
+			return cfg, fmt.Errorf("unknown map %q", impl)
+		}
+	}
+
+	if cfg.keys < 1 || cfg.readsPerEnter < 1 || cfg.commitEvery < 1 {
+		return cfg, fmt.Errorf("keys, reads-per-enter, and commit-every must be positive")
+	}
+
+	return cfg, nil
+}
+
+func newTarget(impl string, cfg config) target {
+	switch impl {
+	case "lrmap":
+		return newLRMapTarget(cfg.commitEvery)
+	case "syncmap":
+		return new(syncMapTarget)
+	case "rwmutex":
+		return &rwMutexTarget{m: make(map[int][]byte)}
+	default:
+		return nil
+	}
+}
+
+// run benchmarks a single map.  The map is populated first, so that reads hit.
+func run(cfg config, impl string) result {
+	t := newTarget(impl, cfg)
+
+	value := make([]byte, cfg.valueSize)
+	write := t.writer()
+
+	for key := 0; key < cfg.keys; key++ {
+		write(key, value)
+	}
+
+	if t, ok := t.(*lrmapTarget); ok {
+		t.m.Commit()
+	}
+
+	var (
+		stop   atomic.Bool
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		res    = result{impl: impl} // nolint:exhaustivestruct
+		before runtime.MemStats
+		after  runtime.MemStats
+	)
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+
+	for i := 0; i < cfg.readers; i++ {
+		wg.Add(1)
+
+		go func(seed uint64) {
+			defer wg.Done()
+
+			read := t.reader()
+			rng := seed
+			ops, latencies := uint64(0), make([]time.Duration, 0, 1024)
+
+			for !stop.Load() {
+				rng = xorshift(rng)
+				key := int(rng % uint64(cfg.keys))
+
+				if ops%sampleEvery == 0 {
+					begin := time.Now()
+					read(key, cfg.readsPerEnter)
+					latencies = append(latencies, time.Since(begin))
+				} else {
+					read(key, cfg.readsPerEnter)
+				}
+
+				ops++
+			}
+
+			mu.Lock()
+			res.reads += ops * uint64(cfg.readsPerEnter)
+			res.readLatencies = append(res.readLatencies, latencies...)
+			mu.Unlock()
+		}(uint64(i) + 1)
+	}
+
+	for i := 0; i < cfg.writers; i++ {
+		wg.Add(1)
+
+		go func(seed uint64) {
+			defer wg.Done()
+
+			write := t.writer()
+			rng := seed
+			ops, latencies := uint64(0), make([]time.Duration, 0, 1024)
+
+			for !stop.Load() {
+				rng = xorshift(rng)
+				key := int(rng % uint64(cfg.keys))
+				value := make([]byte, cfg.valueSize)
+
+				if ops%sampleEvery == 0 {
+					begin := time.Now()
+					write(key, value)
+					latencies = append(latencies, time.Since(begin))
+				} else {
+					write(key, value)
+				}
+
+				ops++
+			}
+
+			mu.Lock()
+			res.writes += ops
+			res.writeLatencies = append(res.writeLatencies, latencies...)
+			mu.Unlock()
+		}(uint64(i) + 1<<32)
+	}
+
+	time.Sleep(cfg.duration)
+	stop.Store(true)
+	wg.Wait()
+
+	res.elapsed = time.Since(start)
+
+	runtime.ReadMemStats(&after)
+	res.allocs = after.Mallocs - before.Mallocs
+
+	return res
+}
+
+func report(w io.Writer, results []result) {
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', tabwriter.AlignRight)
+
+	fmt.Fprintln(tw, "map\treads/s\twrites/s\tread p50\tp99\tp99.9\twrite p50\tp99\tp99.9\tallocs/op\t")
+
+	for _, r := range results {
+		ops := r.reads + r.writes
+		allocs := 0.0
+
+		if ops > 0 {
+			allocs = float64(r.allocs) / float64(ops)
+		}
+
+		fmt.Fprintf(tw, "%s\t%.0f\t%.0f\t%v\t%v\t%v\t%v\t%v\t%v\t%.3f\t\n",
+			r.impl,
+			float64(r.reads)/r.elapsed.Seconds(),
+			float64(r.writes)/r.elapsed.Seconds(),
+			percentile(r.readLatencies, 0.5), percentile(r.readLatencies, 0.99), percentile(r.readLatencies, 0.999),
+			percentile(r.writeLatencies, 0.5), percentile(r.writeLatencies, 0.99), percentile(r.writeLatencies, 0.999),
+			allocs,
+		)
+	}
+
+	_ = tw.Flush()
+}
+
+// percentile returns the p-th percentile of latencies, which it sorts.
+func percentile(latencies []time.Duration, p float64) time.Duration {
+	if len(latencies) == 0 {
+		return 0
+	}
+
+	slices.Sort(latencies)
+
+	return latencies[min(int(float64(len(latencies))*p), len(latencies)-1)]
+}
+
+func xorshift(x uint64) uint64 {
+	x ^= x << 13
+	x ^= x >> 7
+	x ^= x << 17
+
+	return x
+}
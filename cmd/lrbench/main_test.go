@@ -0,0 +1,80 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseFlags(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseFlags([]string{"-impl", "lrmap,rwmutex", "-keys", "10", "-commit-every", "3"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(cfg.impls) != 2 || cfg.impls[0] != "lrmap" || cfg.impls[1] != "rwmutex" {
+		t.Errorf("impls = %q", cfg.impls)
+	}
+
+	if cfg.keys != 10 || cfg.commitEvery != 3 {
+		t.Errorf("keys = %d, commit-every = %d", cfg.keys, cfg.commitEvery)
+	}
+
+	if _, err := parseFlags([]string{"-impl", "btree"}); err == nil {
+		t.Error("unknown map accepted")
+	}
+
+	if _, err := parseFlags([]string{"-keys", "0"}); err == nil {
+		t.Error("zero keys accepted")
+	}
+}
+
+func TestPercentile(t *testing.T) {
+	t.Parallel()
+
+	latencies := []time.Duration{5, 1, 4, 2, 3}
+
+	if got := percentile(latencies, 0.5); got != 3 {
+		t.Errorf("p50 = %d, want 3", got)
+	}
+
+	if got := percentile(latencies, 0.999); got != 5 {
+		t.Errorf("p99.9 = %d, want 5", got)
+	}
+
+	if got := percentile(nil, 0.5); got != 0 {
+		t.Errorf("p50 of nothing = %d, want 0", got)
+	}
+}
+
+func TestRun(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := parseFlags([]string{"-duration", "20ms", "-readers", "2", "-keys", "100", "-reads-per-enter", "4"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := make([]result, 0, len(cfg.impls))
+
+	for _, impl := range cfg.impls {
+		r := run(cfg, impl)
+		if r.reads+r.writes == 0 {
+			t.Errorf("%s: no operations", impl)
+		}
+
+		results = append(results, r)
+	}
+
+	var buf bytes.Buffer
+	report(&buf, results)
+
+	for _, impl := range cfg.impls {
+		if !strings.Contains(buf.String(), impl) {
+			t.Errorf("report lacks %s:\n%s", impl, buf.String())
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"sync"
+	"sync/atomic"
+
+	"github.com/jwkohnen/lrmap"
+)
+
+type (
+	// lrmapTarget commits after every commitEvery writes, counted over all writers.
+	lrmapTarget struct {
+		m           *lrmap.LRMap[int, []byte]
+		commitEvery uint64
+		writes      atomic.Uint64
+	}
+
+	syncMapTarget struct {
+		m sync.Map
+	}
+
+	rwMutexTarget struct {
+		mu sync.RWMutex
+		m  map[int][]byte
+	}
+)
+
+func newLRMapTarget(commitEvery int) *lrmapTarget {
+	// nolint:exhaustivestruct
+	return &lrmapTarget{m: lrmap.New[int, []byte](), commitEvery: uint64(commitEvery)}
+}
+
+func (t *lrmapTarget) reader() func(key, n int) {
+	rh := t.m.NewReadHandler()
+
+	return func(key, n int) {
+		rh.Enter()
+
+		for i := 0; i < n; i++ {
+			_, _ = rh.GetOK(key + i)
+		}
+
+		rh.Leave()
+	}
+}
+
+func (t *lrmapTarget) writer() func(key int, value []byte) {
+	return func(key int, value []byte) {
+		t.m.Set(key, value)
+
+		if t.writes.Add(1)%t.commitEvery == 0 {
+			t.m.Commit()
+		}
+	}
+}
+
+func (t *syncMapTarget) reader() func(key, n int) {
+	return func(key, n int) {
+		for i := 0; i < n; i++ {
+			_, _ = t.m.Load(key + i)
+		}
+	}
+}
+
+func (t *syncMapTarget) writer() func(key int, value []byte) {
+	return func(key int, value []byte) { t.m.Store(key, value) }
+}
+
+func (t *rwMutexTarget) reader() func(key, n int) {
+	return func(key, n int) {
+		t.mu.RLock()
+
+		for i := 0; i < n; i++ {
+			_ = t.m[key+i]
+		}
+
+		t.mu.RUnlock()
+	}
+}
+
+func (t *rwMutexTarget) writer() func(key int, value []byte) {
+	return func(key int, value []byte) {
+		t.mu.Lock()
+		t.m[key] = value
+		t.mu.Unlock()
+	}
+}
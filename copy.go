@@ -0,0 +1,50 @@
+package lrmap
+
+import "maps"
+
+// CopyMap returns a copy of the live view that stays valid after Leave, e.g. to process a
+// consistent view in the background.  If the map has a value copier (see WithValueCopier and
+// Cloner), the copy holds copies of the values, which may be modified; otherwise it shares the
+// values with the map, which must not be modified.
+func (rh *ReadHandler[K, V]) CopyMap() map[K]V {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	if data, ok := rh.inner.live.data.(MapArena[K, V]); ok && rh.inner.lrmap.copier == nil {
+		return maps.Clone(map[K]V(data))
+	}
+
+	dst := make(map[K]V, rh.inner.live.data.Len())
+	rh.copyInto(dst)
+
+	return dst
+}
+
+// CopyInto is like CopyMap, but adds the entries of the live view to dst, overwriting the
+// values of keys dst already has.  Reusing dst saves allocating a new map for every copy.
+func (rh *ReadHandler[K, V]) CopyInto(dst map[K]V) {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	rh.copyInto(dst)
+}
+
+func (rh *ReadHandler[K, V]) copyInto(dst map[K]V) {
+	copier := rh.inner.lrmap.copier
+
+	rh.inner.live.data.Iterate(func(key K, value V) bool {
+		if copier != nil {
+			value = copier(value)
+		}
+
+		dst[key] = value
+
+		return true
+	})
+}
@@ -0,0 +1,80 @@
+package lrmap
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+	"testing"
+)
+
+func TestCopyMap(t *testing.T) {
+	for name, opt := range map[string]Option[int, []int]{
+		"map":   WithArena(NewMapArena[int, []int]),
+		"btree": WithOrderedArena[int, []int](cmp.Compare[int]),
+	} {
+		lrm := New(opt, WithValueCopier[int](slices.Clone[[]int]))
+		rh := lrm.NewReadHandler()
+
+		for i := 0; i < 10; i++ {
+			lrm.Set(i, []int{i})
+		}
+
+		lrm.Commit()
+
+		rh.Enter()
+		copied := rh.CopyMap()
+		dst := map[int][]int{-1: {-1}, 0: nil}
+		rh.CopyInto(dst)
+		rh.Leave()
+
+		if len(copied) != 10 || len(dst) != 11 || dst[-1][0] != -1 {
+			t.Errorf("%s: want 10 entries, and 11 in dst, got %d and %d", name, len(copied), len(dst))
+		}
+
+		for i := 0; i < 10; i++ {
+			if copied[i][0] != i || dst[i][0] != i {
+				t.Errorf("%s: entry %d: got %v and %v", name, i, copied[i], dst[i])
+			}
+		}
+
+		// the copies do not share the values with the map
+		copied[3][0] = -3
+
+		rh.Enter()
+
+		if v := rh.Get(3); v[0] != 3 {
+			t.Errorf("%s: modifying the copy has modified the map: %v", name, v)
+		}
+
+		rh.Leave()
+		rh.Close()
+	}
+}
+
+func TestCopyMapShared(t *testing.T) {
+	lrm := New[int, int]()
+	rh := lrm.NewReadHandler()
+
+	defer rh.Close()
+
+	want := make(map[int]int)
+
+	for i := 0; i < 100; i++ {
+		lrm.Set(i, -i)
+		want[i] = -i
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+	copied := rh.CopyMap()
+	rh.Leave()
+
+	lrm.Set(100, 100)
+	lrm.Commit()
+	lrm.Commit()
+
+	if !maps.Equal(copied, want) {
+		t.Errorf("CopyMap() does not match the committed view")
+	}
+}
@@ -0,0 +1,246 @@
+package lrmap
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// freeHandlers is the number of recycled handlers kept by a map WithExplicitClose.
+const freeHandlers = 64
+
+// WithDebug enables checks for misuse of read handlers that are too expensive to be done by
+// default.  Detected misuse panics.
+//
+// In debug mode, a handler remembers the goroutine that has entered it, and using or leaving
+// it from another goroutine panics with both stacks.
+func WithDebug[K comparable, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.debug = true
+	}
+}
+
+// WithExplicitClose promises that every read handler is closed or recycled, which saves
+// attaching a cleanup to each handler.  A handler that is dropped nonetheless leaks its slot in
+// the reader registry, and if it has been entered, stalls all later commits.  In debug mode
+// (see WithDebug) such a leak panics with the stack that created the handler.
+//
+// Recycled handlers are kept in a small free list instead of a sync.Pool, since the pool
+// drops handlers without closing them.  WithReaderPool sizes the free list.
+//
+// Under TinyGo, on WASI, and with the build tag lrmap_nocleanup, every map behaves as if
+// WithExplicitClose had been given, since handlers cannot be cleaned up there.
+func WithExplicitClose[K comparable, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.explicitClose = true
+		m.freeHandlers = make(chan *ReadHandler[K, V], freeHandlers)
+	}
+}
+
+// leaked is attached as cleanup to handlers of debug maps WithExplicitClose.
+func (m *LRMap[K, V]) leaked(stack []byte) {
+	m.misuse("reader illegal state: handler became unreachable without Close(), created at:\n" + string(stack))
+}
+
+func (m *LRMap[K, V]) misuse(msg string) {
+	if m.onMisuse != nil {
+		m.onMisuse(msg)
+
+		return
+	}
+
+	panic(msg)
+}
+
+// enteredBy records the goroutine that has entered a handler in debug mode.
+type enteredBy struct {
+	goroutine uint64
+	stack     []byte
+	at        time.Time
+
+	// overrun is set once the entry has been reported to exceed WithMaxEnterDuration.
+	overrun atomic.Bool
+}
+
+func (r *readHandlerInner[K, V]) recordEnter() {
+	if r.lrmap.debug {
+		r.enteredBy = &enteredBy{goroutine: goroutineID(), stack: debug.Stack(), at: r.lrmap.clock.Now()}
+		r.slot.enteredBy.Store(r.enteredBy)
+	}
+}
+
+// enteredGoroutine returns the ID of the goroutine that has entered the handler last, or 0
+// if it has not been entered in debug mode.
+func (s *epochSlot) enteredGoroutine() uint64 {
+	if entry := s.enteredBy.Load(); entry != nil {
+		return entry.goroutine
+	}
+
+	return 0
+}
+
+// checkGoroutine reports if an entered handler is used by another goroutine than the one that
+// has entered it.
+func (r *readHandlerInner[K, V]) checkGoroutine() {
+	if !r.lrmap.debug || !r.entered() {
+		return
+	}
+
+	if id := goroutineID(); id != r.enteredBy.goroutine {
+		r.lrmap.misuse(fmt.Sprintf(
+			"reader illegal state: entered by goroutine %d, but used by goroutine %d\n"+
+				"entered at:\n%s\nused at:\n%s",
+			r.enteredBy.goroutine, id, r.enteredBy.stack, debug.Stack(),
+		))
+	}
+}
+
+// EnterOverrun reports a read handler that has stayed entered for longer than allowed by
+// WithMaxEnterDuration.
+type EnterOverrun struct {
+	ReaderInfo
+	Entered time.Duration
+
+	// Stack is the stack of the goroutine at the time it has entered the handler.
+	Stack string
+}
+
+// WithMaxEnterDuration makes a read handler of a map in debug mode (see WithDebug) that stays
+// entered for longer than d panic with the stack at which it has been entered.  This turns a
+// reader that stalls commits into a failure that points at the culprit.
+//
+// The handler is checked when it leaves, and by commits while they wait for it; the panic thus
+// happens in either the reader's or the committer's goroutine.  If fn is not nil, it is called
+// instead of panicking, at most once per entry.  fn may be called with the writer lock held and
+// must not call into the map.
+func WithMaxEnterDuration[K comparable, V any](d time.Duration, fn func(EnterOverrun)) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.maxEnter = d
+		m.onOverrun = fn
+	}
+}
+
+// checkEnterDuration reports an entry of a reader that has exceeded WithMaxEnterDuration.
+func (m *LRMap[K, V]) checkEnterDuration(entry *enteredBy, owner *ReaderInfo) {
+	if !m.debug || m.maxEnter <= 0 || entry == nil {
+		return
+	}
+
+	entered := m.clock.Now().Sub(entry.at)
+	if entered <= m.maxEnter || !entry.overrun.CompareAndSwap(false, true) {
+		return
+	}
+
+	if m.onOverrun != nil {
+		m.onOverrun(EnterOverrun{ReaderInfo: *owner, Entered: entered, Stack: string(entry.stack)})
+
+		return
+	}
+
+	m.misuse(fmt.Sprintf(
+		"reader illegal state: reader %d (label %q) has been entered for %v, longer than %v\n"+
+			"entered at:\n%s",
+		owner.ID, owner.Label, entered, m.maxEnter, entry.stack,
+	))
+}
+
+// goroutineID parses the ID of the calling goroutine from the header of its stack trace,
+// which looks like "goroutine 42 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+
+	header := buf[:runtime.Stack(buf[:], false)]
+	header = bytes.TrimPrefix(header, []byte("goroutine "))
+
+	if i := bytes.IndexByte(header, ' '); i >= 0 {
+		header = header[:i]
+	}
+
+	id, err := strconv.ParseUint(string(header), 10, 64)
+	if err != nil {
+		panic(fmt.Errorf("cannot parse goroutine ID: %w", err))
+	}
+
+	return id
+}
+
+// ReaderStack is the stack of the goroutine that has entered a reader.
+type ReaderStack struct {
+	ReaderInfo
+	Goroutine uint64
+	Stack     string
+}
+
+// WithStragglerStacks makes a commit in debug mode (see WithDebug) that has waited for readers
+// for threshold call fn with the stacks of the goroutines that have entered the readers it still
+// waits for.  fn is called at most once per commit, with the writer lock held.
+func WithStragglerStacks[K comparable, V any](
+	threshold time.Duration,
+	fn func(gen uint64, stacks []ReaderStack),
+) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.stragglerThreshold = threshold
+		m.stragglerStacks = fn
+	}
+}
+
+// readerStacks returns the stacks of the goroutines that have entered readers.  Goroutines that
+// have exited in the meantime have no stack.
+func readerStacks(readers []enteredReader) []ReaderStack {
+	buf := make([]byte, 1<<16)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+
+			break
+		}
+
+		buf = make([]byte, 2*len(buf))
+	}
+
+	// All stacks start with a header like "goroutine 42 [running]:" and are separated by an
+	// empty line.
+	byID := make(map[uint64]string)
+
+	for _, stack := range strings.Split(string(buf), "\n\n") {
+		var id uint64
+		if _, err := fmt.Sscanf(stack, "goroutine %d ", &id); err == nil {
+			byID[id] = stack
+		}
+	}
+
+	stacks := make([]ReaderStack, 0, len(readers))
+
+	for _, r := range readers {
+		id := r.slot.enteredGoroutine()
+		stacks = append(stacks, ReaderStack{ReaderInfo: *r.owner, Goroutine: id, Stack: byID[id]})
+	}
+
+	return stacks
+}
+
+// checkSelfWait reports in debug mode if the calling goroutine is about to wait for readers it
+// has entered itself, e.g. by committing while entered, which would wait forever.
+func (m *LRMap[K, V]) checkSelfWait(readers []enteredReader) {
+	if !m.debug || len(readers) == 0 {
+		return
+	}
+
+	id := goroutineID()
+
+	for _, r := range readers {
+		if r.slot.enteredGoroutine() == id {
+			m.misuse(fmt.Sprintf(
+				"illegal use: goroutine %d waits for reader %d (label %q), which it has entered "+
+					"itself and thus never leaves\nwaiting at:\n%s",
+				id, r.owner.ID, r.owner.Label, debug.Stack(),
+			))
+		}
+	}
+}
@@ -0,0 +1,258 @@
+package lrmap
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExplicitCloseRecycle(t *testing.T) {
+	lrm := New(WithExplicitClose[int, int]())
+
+	rh := lrm.NewReadHandler()
+	rh.Recycle()
+
+	if again := lrm.NewReadHandler(); again != rh {
+		t.Errorf("recycled handler has not been reused")
+	}
+}
+
+func TestExplicitCloseLeak(t *testing.T) {
+	if !cleanupsSupported {
+		t.Skip("handlers are not cleaned up in this build")
+	}
+
+	lrm := New(WithExplicitClose[int, int](), WithDebug[int, int]())
+
+	leaks := make(chan string, 1)
+	lrm.onMisuse = func(msg string) { leaks <- msg }
+
+	closed := lrm.NewReadHandler()
+	closed.Close()
+
+	func() {
+		_ = lrm.NewReadHandler()
+	}()
+
+	for i := 0; i < 10; i++ {
+		runtime.GC()
+
+		select {
+		case msg := <-leaks:
+			if !strings.Contains(msg, "TestExplicitCloseLeak") {
+				t.Errorf("leak report does not contain the creating stack:\n%s", msg)
+			}
+
+			return
+		default:
+			runtime.Gosched()
+		}
+	}
+
+	t.Fatal("leaked handler has not been reported")
+}
+
+func TestCrossGoroutineUse(t *testing.T) {
+	lrm := New(WithDebug[int, int]())
+
+	var misuse string
+	lrm.onMisuse = func(msg string) { misuse = msg }
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	_ = rh.Get(1)
+
+	if misuse != "" {
+		t.Fatalf("same goroutine reported as misuse:\n%s", misuse)
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		rh.Leave()
+	}()
+
+	<-done
+
+	if !strings.Contains(misuse, "entered at:") || !strings.Contains(misuse, "TestCrossGoroutineUse") {
+		t.Errorf("misuse report lacks the stacks:\n%s", misuse)
+	}
+}
+
+func TestStragglerStacks(t *testing.T) {
+	var (
+		stacks  []ReaderStack
+		release = make(chan struct{})
+	)
+
+	lrm := New(
+		WithDebug[int, int](),
+		WithStragglerStacks[int, int](0, func(_ uint64, s []ReaderStack) {
+			stacks = s
+			close(release)
+		}),
+	)
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.SetLabel("blocking")
+
+	entered := make(chan struct{})
+
+	go func() {
+		rh.Enter()
+		close(entered)
+		blockingReader(release)
+		rh.Leave()
+	}()
+
+	<-entered
+	lrm.Commit()
+
+	if len(stacks) != 1 || stacks[0].Label != "blocking" || !strings.Contains(stacks[0].Stack, "blockingReader") {
+		t.Errorf("straggler stacks do not point at the blocking reader: %+v", stacks)
+	}
+}
+
+func blockingReader(release <-chan struct{}) { <-release }
+
+func TestCommitWhileEntered(t *testing.T) {
+	lrm := New(WithDebug[int, int]())
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.SetLabel("self")
+
+	var misuse string
+	lrm.onMisuse = func(msg string) {
+		misuse = msg
+
+		// let the commit go on instead of hanging the test
+		rh.Leave()
+	}
+
+	rh.Enter()
+	lrm.Commit()
+
+	if !strings.Contains(misuse, `reader 1 (label "self")`) || !strings.Contains(misuse, "TestCommitWhileEntered") {
+		t.Errorf("misuse report lacks the reader or the stack:\n%s", misuse)
+	}
+
+	// other goroutines' readers are fine
+	misuse = ""
+	entered, leave := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		other := lrm.NewReadHandler()
+		defer other.Close()
+
+		other.Enter()
+		close(entered)
+		<-leave
+		other.Leave()
+	}()
+
+	<-entered
+	go close(leave)
+	lrm.Commit()
+
+	if misuse != "" {
+		t.Errorf("waiting for another goroutine reported as misuse:\n%s", misuse)
+	}
+}
+
+func TestMaxEnterDuration(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+
+	var overruns []EnterOverrun
+
+	lrm := New(
+		WithDebug[int, int](),
+		WithClock[int, int](clock),
+		WithMaxEnterDuration[int, int](time.Second, func(o EnterOverrun) { overruns = append(overruns, o) }),
+	)
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	clock.now = clock.now.Add(time.Second)
+	rh.Leave()
+
+	if len(overruns) != 0 {
+		t.Fatalf("reported entry of exactly the limit: %+v", overruns)
+	}
+
+	rh.SetLabel("slow")
+	rh.Enter()
+	clock.now = clock.now.Add(2 * time.Second)
+	rh.Leave()
+
+	if len(overruns) != 1 {
+		t.Fatalf("want 1 overrun, got %d", len(overruns))
+	}
+
+	o := overruns[0]
+	if o.Label != "slow" || o.Entered != 2*time.Second || !strings.Contains(o.Stack, "TestMaxEnterDuration") {
+		t.Errorf("overrun lacks the reader, the duration or the stack: %+v", o)
+	}
+}
+
+func TestMaxEnterDurationPanics(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	lrm := New(WithDebug[int, int](), WithClock[int, int](clock), WithMaxEnterDuration[int, int](time.Second, nil))
+
+	var misuse string
+	lrm.onMisuse = func(msg string) { misuse = msg }
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	clock.now = clock.now.Add(time.Minute)
+	rh.Leave()
+
+	if !strings.Contains(misuse, "longer than 1s") || !strings.Contains(misuse, "TestMaxEnterDurationPanics") {
+		t.Errorf("misuse report lacks the limit or the stack:\n%s", misuse)
+	}
+}
+
+func TestMaxEnterDurationCommit(t *testing.T) {
+	overrun := make(chan EnterOverrun, 1)
+	lrm := New(
+		WithDebug[int, int](),
+		WithMaxEnterDuration[int, int](10*time.Millisecond, func(o EnterOverrun) { overrun <- o }),
+	)
+
+	entered, done := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		rh := lrm.NewReadHandler()
+		defer rh.Close()
+
+		rh.Enter()
+		close(entered)
+
+		// stay entered until the committer has reported the overrun
+		o := <-overrun
+		rh.Leave()
+
+		if !strings.Contains(o.Stack, "TestMaxEnterDurationCommit") {
+			t.Errorf("overrun lacks the stack at Enter():\n%s", o.Stack)
+		}
+	}()
+
+	<-entered
+	lrm.Set(1, 1)
+	lrm.Commit()
+	<-done
+}
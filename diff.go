@@ -0,0 +1,134 @@
+package lrmap
+
+// Diff lists the keys that differ between two generations of a map.  The keys are not
+// ordered.
+type Diff[K comparable] struct {
+	From, To uint64
+	Added    []K
+	Removed  []K
+	Changed  []K
+}
+
+type differ[K comparable, V any] struct {
+	eq   func(V, V) bool
+	last Diff[K]
+}
+
+// WithDiff makes Commit record which keys it has changed, see LastDiff.  If eq is nil, every
+// key that has been set is reported as changed, even if its value did not change.
+func WithDiff[K comparable, V any](eq func(V, V) bool) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		// nolint:exhaustivestruct
+		m.diff = &differ[K, V]{eq: eq}
+	}
+}
+
+// LastDiff returns the changes published by the most recent Commit.  It requires WithDiff.
+func (m *LRMap[K, V]) LastDiff() Diff[K] {
+	if m.diff == nil {
+		panic("illegal use: LastDiff() requires WithDiff()")
+	}
+
+	m.lock()
+	defer m.mu.Unlock()
+
+	return m.diff.last
+}
+
+// PendingDiff returns the changes that the next Commit would publish, i.e. the difference
+// between the write map and the committed view (writes queued by SetAsync are not included
+// yet).  Values are compared with the function given to WithDiff; without WithDiff, every key
+// that has been set is reported as changed.  Like LastDiff, it only looks at the keys in the
+// redo log.
+func (m *LRMap[K, V]) PendingDiff() Diff[K] {
+	m.lock()
+	defer m.mu.Unlock()
+
+	m.syncAll()
+
+	var d differ[K, V]
+	if m.diff != nil {
+		d.eq = m.diff.eq
+	}
+
+	diff := d.compute(m.readMap.Load().data, m.writeMap.Load().data, m.redoLog, m.hasPrefix)
+	diff.From, diff.To = m.generation, m.generation+1
+
+	return diff
+}
+
+// DiffMaps compares two snapshots.  If eq is nil, values are not compared and Changed is
+// empty.
+func DiffMaps[K comparable, V any](from, to map[K]V, eq func(V, V) bool) Diff[K] {
+	var diff Diff[K]
+
+	for key, old := range from {
+		value, ok := to[key]
+
+		switch {
+		case !ok:
+			diff.Removed = append(diff.Removed, key)
+		case eq != nil && !eq(old, value):
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			diff.Added = append(diff.Added, key)
+		}
+	}
+
+	return diff
+}
+
+// compute derives the diff from the redo log, so that only the keys touched by the commit
+// need to be looked at (and, for prefix deletions, the keys of prev with that prefix).  prev
+// must still hold the previous generation, i.e. the redo log must not have been replayed yet.
+func (d *differ[K, V]) compute(
+	prev, next Arena[K, V],
+	redoLog []operation[K, V],
+	hasPrefix func(key, prefix K) bool,
+) Diff[K] {
+	var diff Diff[K]
+
+	seen := make(map[K]struct{}, len(redoLog))
+
+	visit := func(key K) {
+		if _, ok := seen[key]; ok {
+			return
+		}
+
+		seen[key] = struct{}{}
+
+		old, existed := prev.Get(key)
+		value, exists := next.Get(key)
+
+		switch {
+		case !existed && exists:
+			diff.Added = append(diff.Added, key)
+		case existed && !exists:
+			diff.Removed = append(diff.Removed, key)
+		case existed && exists && (d.eq == nil || !d.eq(old, value)):
+			diff.Changed = append(diff.Changed, key)
+		}
+	}
+
+	for _, op := range redoLog {
+		if op.typ != OpDeletePrefix {
+			visit(op.key)
+
+			continue
+		}
+
+		prev.Iterate(func(key K, _ V) bool {
+			if hasPrefix(key, op.key) {
+				visit(key)
+			}
+
+			return true
+		})
+	}
+
+	return diff
+}
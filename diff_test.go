@@ -0,0 +1,83 @@
+package lrmap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLastDiff(t *testing.T) {
+	lrm := New(WithDiff[string, int](func(a, b int) bool { return a == b }))
+
+	lrm.Set("keep", 1)
+	lrm.Set("change", 1)
+	lrm.Set("remove", 1)
+	lrm.Set("same", 1)
+	lrm.Commit()
+
+	if d := lrm.LastDiff(); len(d.Added) != 4 || d.From != 0 || d.To != 1 {
+		t.Errorf("LastDiff() after first Commit, want 4 additions from 0 to 1, got %+v", d)
+	}
+
+	lrm.Set("change", 2)
+	lrm.Set("change", 3)
+	lrm.Delete("remove")
+	lrm.Set("same", 1)
+	lrm.Set("add", 1)
+	lrm.Set("transient", 1)
+	lrm.Delete("transient")
+
+	pending := lrm.PendingDiff()
+
+	lrm.Commit()
+
+	d := lrm.LastDiff()
+	if !slices.Equal(d.Added, []string{"add"}) ||
+		!slices.Equal(d.Removed, []string{"remove"}) ||
+		!slices.Equal(d.Changed, []string{"change"}) ||
+		d.From != 1 || d.To != 2 {
+		t.Errorf("LastDiff(), got %+v", d)
+	}
+
+	if !slices.Equal(pending.Added, d.Added) ||
+		!slices.Equal(pending.Removed, d.Removed) ||
+		!slices.Equal(pending.Changed, d.Changed) ||
+		pending.From != 1 || pending.To != 2 {
+		t.Errorf("PendingDiff() before the commit, want %+v, got %+v", d, pending)
+	}
+
+	if d := lrm.PendingDiff(); len(d.Added)+len(d.Removed)+len(d.Changed) != 0 {
+		t.Errorf("PendingDiff() after the commit, want no changes, got %+v", d)
+	}
+}
+
+func TestPendingDiffWithoutEq(t *testing.T) {
+	lrm := New[string, int](WithReplayChunk[string, int](1))
+
+	lrm.Set("a", 1)
+	lrm.Set("b", 1)
+	lrm.Set("c", 1)
+	lrm.Commit()
+
+	lrm.Set("a", 1)
+	lrm.Delete("b")
+	lrm.Set("d", 1)
+
+	d := lrm.PendingDiff()
+	if !slices.Equal(d.Added, []string{"d"}) ||
+		!slices.Equal(d.Removed, []string{"b"}) ||
+		!slices.Equal(d.Changed, []string{"a"}) {
+		t.Errorf("PendingDiff(), got %+v", d)
+	}
+}
+
+func TestDiffMaps(t *testing.T) {
+	d := DiffMaps(
+		map[int]int{1: 1, 2: 2, 3: 3},
+		map[int]int{2: 2, 3: 4, 5: 5},
+		func(a, b int) bool { return a == b },
+	)
+
+	if !slices.Equal(d.Added, []int{5}) || !slices.Equal(d.Removed, []int{1}) || !slices.Equal(d.Changed, []int{3}) {
+		t.Errorf("DiffMaps(), got %+v", d)
+	}
+}
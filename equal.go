@@ -0,0 +1,36 @@
+package lrmap
+
+// EqualCommitted reports whether the committed view has exactly the keys of other, with values
+// equal according to eq.
+func (m *LRMap[K, V]) EqualCommitted(other map[K]V, eq func(V, V) bool) bool {
+	m.lock()
+	defer m.mu.Unlock()
+
+	return equal(m.readMap.Load().data, other, eq)
+}
+
+// Equal reports whether the live view has exactly the keys of other, with values equal
+// according to eq.
+func (rh *ReadHandler[K, V]) Equal(other map[K]V, eq func(V, V) bool) bool {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	return equal(rh.inner.live.data, other, eq)
+}
+
+func equal[K comparable, V any](data Arena[K, V], other map[K]V, eq func(V, V) bool) bool {
+	if data.Len() != len(other) {
+		return false
+	}
+
+	for key, want := range other {
+		if value, ok := data.Get(key); !ok || !eq(value, want) {
+			return false
+		}
+	}
+
+	return true
+}
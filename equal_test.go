@@ -0,0 +1,42 @@
+package lrmap
+
+import "testing"
+
+func TestEqual(t *testing.T) {
+	eq := func(a, b int) bool { return a == b }
+
+	lrm := New[string, int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", 1)
+	lrm.Set("b", 2)
+
+	if lrm.EqualCommitted(map[string]int{"a": 1, "b": 2}, eq) {
+		t.Errorf("EqualCommitted() before Commit, want false")
+	}
+
+	lrm.Commit()
+
+	for _, tc := range []struct {
+		other map[string]int
+		want  bool
+	}{
+		{map[string]int{"a": 1, "b": 2}, true},
+		{map[string]int{"a": 1, "b": 3}, false},
+		{map[string]int{"a": 1, "c": 2}, false},
+		{map[string]int{"a": 1}, false},
+		{nil, false},
+	} {
+		if got := lrm.EqualCommitted(tc.other, eq); got != tc.want {
+			t.Errorf("EqualCommitted(%v), want %t, got %t", tc.other, tc.want, got)
+		}
+
+		rh.Enter()
+		if got := rh.Equal(tc.other, eq); got != tc.want {
+			t.Errorf("Equal(%v), want %t, got %t", tc.other, tc.want, got)
+		}
+		rh.Leave()
+	}
+}
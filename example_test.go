@@ -33,3 +33,53 @@ func ExampleReadHandler_Iterate() {
 	// Output:
 	// [0 1 2 3 4 5 6 7 8 9]
 }
+
+func ExampleAllSorted() {
+	m := New[string, int]()
+
+	m.Set("c", 3)
+	m.Set("a", 1)
+	m.Set("b", 2)
+
+	m.Commit()
+
+	rh := m.NewReadHandler()
+	rh.Enter()
+	defer rh.Close()
+
+	for k, v := range AllSorted(rh) {
+		fmt.Println(k, v)
+	}
+
+	// Output:
+	// a 1
+	// b 2
+	// c 3
+}
+
+func ExampleKey2() {
+	m := New[Key2[string, int], string](WithOrderedArena[Key2[string, int], string](CompareKey2[string, int]))
+
+	m.Set(MakeKey2("acme", 2), "bob")
+	m.Set(MakeKey2("acme", 1), "alice")
+	m.Set(MakeKey2("initech", 1), "peter")
+
+	m.Commit()
+
+	rh := m.NewReadHandler()
+	rh.Enter()
+	defer rh.Close()
+
+	fmt.Println(rh.Get(MakeKey2("initech", 1)))
+
+	rh.Range(MakeKey2("acme", 0), MakeKey2("acme\x00", 0), func(k Key2[string, int], v string) bool {
+		fmt.Println(k, v)
+
+		return true
+	})
+
+	// Output:
+	// peter
+	// (acme, 1) alice
+	// (acme, 2) bob
+}
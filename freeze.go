@@ -0,0 +1,56 @@
+package lrmap
+
+import "errors"
+
+// ErrFrozen is returned by write operations on a map that has been frozen.
+var ErrFrozen = errors.New("map is frozen")
+
+// Freeze commits all pending writes and turns the map read-only for good: it drops the second
+// arena and the redo log, so that the map holds a single copy of its entries, and read handlers
+// no longer record when they enter and leave.  Afterwards, writes are dropped (or fail with
+// ErrFrozen), while the writer's reads see the frozen entries.  Freezing a frozen map is a
+// no-op.
+//
+// Freeze suits maps that are built once at startup and never written again.
+func (m *LRMap[K, V]) Freeze() error {
+	m.lock()
+
+	if m.closed.Load() {
+		m.mu.Unlock()
+
+		return ErrClosed
+	}
+
+	if m.frozen.Load() {
+		m.mu.Unlock()
+
+		return nil
+	}
+
+	if err := m.commit(false); err != nil {
+		m.mu.Unlock()
+
+		return err
+	}
+
+	// No reader can use the write map after the commit, and from now on, none will.
+	stale := m.writeMap.Load()
+	m.writeMap.Store(m.readMap.Load())
+	stale.data = nil
+	stale.meta = nil
+
+	m.redoLog = nil
+	m.notifyDrained()
+	m.redoIndex = nil
+	m.backlog = nil
+	m.dropHistory()
+
+	m.frozen.Store(true)
+
+	m.completeCommit()
+
+	return nil
+}
+
+// Frozen reports whether the map has been frozen.
+func (m *LRMap[K, V]) Frozen() bool { return m.frozen.Load() }
@@ -0,0 +1,66 @@
+package lrmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFreeze(t *testing.T) {
+	lrm := New[string, int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", 1)
+	lrm.Set("b", 2)
+
+	if err := lrm.Freeze(); err != nil {
+		t.Fatalf("Freeze(): %v", err)
+	}
+
+	if err := lrm.Freeze(); err != nil {
+		t.Errorf("Freeze() twice: %v", err)
+	}
+
+	if !lrm.Frozen() {
+		t.Errorf("Frozen(): want true")
+	}
+
+	if err := lrm.TrySet("c", 3); !errors.Is(err, ErrFrozen) {
+		t.Errorf("TrySet(): want ErrFrozen, got %v", err)
+	}
+
+	if err := lrm.TryCommit(); !errors.Is(err, ErrFrozen) {
+		t.Errorf("TryCommit(): want ErrFrozen, got %v", err)
+	}
+
+	lrm.Delete("a")
+
+	if v, ok := lrm.Pop("b"); !ok || v != 2 {
+		t.Errorf("Pop(b): want 2, true, got %d, %t", v, ok)
+	}
+
+	if got := lrm.Get("a"); got != 1 {
+		t.Errorf("writer Get(a): want 1, got %d", got)
+	}
+
+	if lrm.left.data != nil && lrm.right.data != nil {
+		t.Errorf("want one arena released")
+	}
+
+	rh.Enter()
+
+	if got := rh.Len(); got != 2 {
+		t.Errorf("Len(): want 2, got %d", got)
+	}
+
+	if got := lrm.Stats().ActiveReaders; got != 0 {
+		t.Errorf("ActiveReaders: want 0 for untracked readers, got %d", got)
+	}
+
+	rh.Leave()
+
+	if rh.inner.entered() {
+		t.Errorf("entered() after Leave(): want false")
+	}
+}
@@ -0,0 +1,170 @@
+package lrmap
+
+type (
+	// FuncMap is a left-right map for key types that are not comparable (byte slices, structs
+	// containing slices, ...) or whose notion of equality differs from Go's (case-folded
+	// strings, ...).  Keys are hashed with a user supplied function into buckets that are
+	// resolved with a user supplied equality function.
+	FuncMap[K any, V any] struct {
+		lrmap *LRMap[uint64, funcBucket[K, V]]
+		hash  func(K) uint64
+		eq    func(K, K) bool
+	}
+
+	// funcBucket holds all entries whose keys share the same hash.  Buckets are shared by
+	// both arenas after the redo log has been replayed, so they are never modified in place,
+	// but copied on write instead.
+	funcBucket[K any, V any] []funcEntry[K, V]
+
+	funcEntry[K any, V any] struct {
+		key   K
+		value V
+	}
+)
+
+// NewFunc returns a FuncMap that uses hash and eq to resolve keys.  Keys that are equal
+// according to eq must have the same hash.
+func NewFunc[K any, V any](hash func(K) uint64, eq func(K, K) bool) *FuncMap[K, V] {
+	return &FuncMap[K, V]{
+		lrmap: New[uint64, funcBucket[K, V]](),
+		hash:  hash,
+		eq:    eq,
+	}
+}
+
+func (m *FuncMap[K, V]) Set(key K, value V) {
+	m.lrmap.lock()
+	defer m.lrmap.mu.Unlock()
+
+	h := m.hash(key)
+	m.lrmap.syncKey(h)
+	bucket, _ := m.lrmap.writeMap.Load().data.Get(h)
+
+	m.lrmap.set(h, bucket.with(key, value, m.eq))
+}
+
+func (m *FuncMap[K, V]) Delete(key K) {
+	m.lrmap.lock()
+	defer m.lrmap.mu.Unlock()
+
+	h := m.hash(key)
+	m.lrmap.syncKey(h)
+
+	bucket, ok := m.lrmap.writeMap.Load().data.Get(h)
+	if !ok || bucket.index(key, m.eq) < 0 {
+		return
+	}
+
+	if bucket = bucket.without(key, m.eq); len(bucket) == 0 {
+		m.lrmap.delete(h)
+	} else {
+		m.lrmap.set(h, bucket)
+	}
+}
+
+func (m *FuncMap[K, V]) Get(key K) V {
+	value, _ := m.GetOK(key)
+
+	return value
+}
+
+func (m *FuncMap[K, V]) GetOK(key K) (V, bool) {
+	return m.lrmap.Get(m.hash(key)).lookup(key, m.eq)
+}
+
+func (m *FuncMap[K, V]) Commit() { m.lrmap.Commit() }
+
+func (m *FuncMap[K, V]) NewReadHandler() *FuncReadHandler[K, V] {
+	return &FuncReadHandler[K, V]{rh: m.lrmap.NewReadHandler(), hash: m.hash, eq: m.eq}
+}
+
+type FuncReadHandler[K any, V any] struct {
+	rh   *ReadHandler[uint64, funcBucket[K, V]]
+	hash func(K) uint64
+	eq   func(K, K) bool
+}
+
+func (rh *FuncReadHandler[K, V]) Enter()   { rh.rh.Enter() }
+func (rh *FuncReadHandler[K, V]) Leave()   { rh.rh.Leave() }
+func (rh *FuncReadHandler[K, V]) Close()   { rh.rh.Close() }
+func (rh *FuncReadHandler[K, V]) Recycle() { rh.rh.Recycle() }
+
+func (rh *FuncReadHandler[K, V]) Get(key K) V {
+	value, _ := rh.GetOK(key)
+
+	return value
+}
+
+func (rh *FuncReadHandler[K, V]) GetOK(key K) (V, bool) {
+	return rh.rh.Get(rh.hash(key)).lookup(key, rh.eq)
+}
+
+// Len counts the entries of all buckets and thus is linear in the number of distinct hashes.
+func (rh *FuncReadHandler[K, V]) Len() int {
+	n := 0
+
+	rh.rh.Iterate(func(_ uint64, bucket funcBucket[K, V]) bool {
+		n += len(bucket)
+
+		return true
+	})
+
+	return n
+}
+
+func (rh *FuncReadHandler[K, V]) Iterate(fn func(_ K, _ V) bool) {
+	rh.rh.Iterate(func(_ uint64, bucket funcBucket[K, V]) bool {
+		for _, e := range bucket {
+			if ok := fn(e.key, e.value); !ok {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func (b funcBucket[K, V]) index(key K, eq func(K, K) bool) int {
+	for i := range b {
+		if eq(b[i].key, key) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (b funcBucket[K, V]) lookup(key K, eq func(K, K) bool) (V, bool) {
+	if i := b.index(key, eq); i >= 0 {
+		return b[i].value, true
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+func (b funcBucket[K, V]) with(key K, value V, eq func(K, K) bool) funcBucket[K, V] {
+	nb := make(funcBucket[K, V], len(b), len(b)+1)
+	copy(nb, b)
+
+	if i := nb.index(key, eq); i >= 0 {
+		nb[i].value = value
+	} else {
+		nb = append(nb, funcEntry[K, V]{key: key, value: value})
+	}
+
+	return nb
+}
+
+func (b funcBucket[K, V]) without(key K, eq func(K, K) bool) funcBucket[K, V] {
+	nb := make(funcBucket[K, V], 0, len(b))
+
+	for _, e := range b {
+		if !eq(e.key, key) {
+			nb = append(nb, e)
+		}
+	}
+
+	return nb
+}
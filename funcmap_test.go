@@ -0,0 +1,99 @@
+package lrmap
+
+import (
+	"bytes"
+	"hash/fnv"
+	"strings"
+	"testing"
+)
+
+func TestFuncMapBytes(t *testing.T) {
+	hash := func(k []byte) uint64 {
+		h := fnv.New64a()
+		_, _ = h.Write(k)
+
+		return h.Sum64()
+	}
+
+	lrm := NewFunc[[]byte, int](hash, bytes.Equal)
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set([]byte("one"), 1)
+	lrm.Set([]byte("two"), 2)
+	lrm.Set([]byte("three"), 3)
+	lrm.Set([]byte("two"), 22)
+	lrm.Delete([]byte("three"))
+
+	if v, ok := lrm.GetOK([]byte("two")); !ok || v != 22 {
+		t.Errorf("writer GetOK(two), want (22, true), got (%d, %t)", v, ok)
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+
+	if n := rh.Len(); n != 2 {
+		t.Errorf("Len(), want 2, got %d", n)
+	}
+
+	if v := rh.Get([]byte("one")); v != 1 {
+		t.Errorf("Get(one), want 1, got %d", v)
+	}
+
+	if v, ok := rh.GetOK([]byte("three")); ok {
+		t.Errorf("GetOK(three), want (0, false), got (%d, %t)", v, ok)
+	}
+
+	rh.Leave()
+}
+
+func TestFuncMapCollisions(t *testing.T) {
+	// a constant hash forces all keys into one bucket
+	lrm := NewFunc[string, int](func(string) uint64 { return 42 }, strings.EqualFold)
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("Alpha", 1)
+	lrm.Set("beta", 2)
+	lrm.Set("ALPHA", 3)
+	lrm.Commit()
+
+	rh.Enter()
+
+	if v := rh.Get("alpha"); v != 3 {
+		t.Errorf("Get(alpha), want 3, got %d", v)
+	}
+
+	sum := 0
+
+	rh.Iterate(func(_ string, v int) bool {
+		sum += v
+
+		return true
+	})
+
+	if sum != 5 {
+		t.Errorf("Iterate: want sum 5, got %d", sum)
+	}
+
+	rh.Leave()
+
+	lrm.Delete("BETA")
+	lrm.Delete("gamma")
+	lrm.Commit()
+
+	rh.Enter()
+
+	if _, ok := rh.GetOK("beta"); ok {
+		t.Errorf("GetOK(beta) after Delete, want false")
+	}
+
+	if n := rh.Len(); n != 1 {
+		t.Errorf("Len(), want 1, got %d", n)
+	}
+
+	rh.Leave()
+}
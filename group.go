@@ -0,0 +1,118 @@
+package lrmap
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// CommitGroup commits several maps (of possibly different types) together: all maps are
+	// locked, vetoed, persisted, and published before any of them waits for its readers.  A
+	// group commit publishes either all maps or none; however, the persisters of the maps
+	// are not coordinated, so a failing persister may leave the batches of other maps
+	// persisted.
+	//
+	// Readers that enter the maps after Commit has returned see all updates.  Readers that
+	// need to enter several maps consistently while a group commit may be publishing use a
+	// ReadTx.
+	CommitGroup struct {
+		mu      sync.Mutex
+		members []groupMember
+
+		// seq is odd while a group commit publishes its maps.
+		seq atomic.Uint64
+	}
+
+	// groupMember is the part of LRMap that a CommitGroup drives.
+	groupMember interface {
+		lock()
+		unlock()
+		vetoCommit() error
+		persistCommit() error
+		publish()
+		finishCommit()
+		completeCommit()
+	}
+)
+
+func NewCommitGroup() *CommitGroup {
+	return new(CommitGroup)
+}
+
+// Join adds m to g.  Commits of g lock their maps in the order they have joined.  A map must
+// not join more than one group.
+func Join[K comparable, V any](g *CommitGroup, m *LRMap[K, V]) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.group != nil {
+		panic("illegal use: map has already joined a commit group")
+	}
+
+	m.group = g
+	g.members = append(g.members, m)
+}
+
+// Commit commits all maps of the group.  If any pre-commit hook or persister fails, no map is
+// published and the error is returned.
+func (g *CommitGroup) Commit() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, m := range g.members {
+		m.lock()
+	}
+
+	if err := g.prepare(); err != nil {
+		for _, m := range g.members {
+			m.unlock()
+		}
+
+		return err
+	}
+
+	g.seq.Add(1)
+
+	for _, m := range g.members {
+		m.publish()
+	}
+
+	g.seq.Add(1)
+
+	for _, m := range g.members {
+		m.finishCommit()
+	}
+
+	for _, m := range g.members {
+		m.completeCommit()
+	}
+
+	return nil
+}
+
+func (g *CommitGroup) prepare() error {
+	for _, m := range g.members {
+		if err := m.vetoCommit(); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range g.members {
+		if err := m.persistCommit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// lock acquires m.mu once no pipelined commit is in flight (see WithPipelinedCommits).
+func (m *LRMap[K, V]) lock() {
+	m.mu.Lock()
+	m.awaitCommit()
+}
+
+func (m *LRMap[K, V]) unlock() { m.mu.Unlock() }
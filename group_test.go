@@ -0,0 +1,106 @@
+package lrmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCommitGroup(t *testing.T) {
+	errVeto := errors.New("veto")
+	veto := false
+
+	users := New[int, string]()
+	byName := New(WithPreCommit(func([]Op[string, int]) error {
+		if veto {
+			return errVeto
+		}
+
+		return nil
+	}))
+
+	g := NewCommitGroup()
+	Join(g, users)
+	Join(g, byName)
+
+	users.Set(1, "alice")
+	byName.Set("alice", 1)
+
+	if err := g.Commit(); err != nil {
+		t.Fatalf("Commit(): %v", err)
+	}
+
+	if users.Stats().CommittedLen != 1 || byName.Stats().CommittedLen != 1 {
+		t.Errorf("group commit has not published all maps")
+	}
+
+	users.Set(2, "bob")
+	byName.Set("bob", 2)
+
+	veto = true
+
+	if err := g.Commit(); !errors.Is(err, errVeto) {
+		t.Errorf("Commit(), want %v, got %v", errVeto, err)
+	}
+
+	if users.Stats().CommittedLen != 1 {
+		t.Errorf("vetoed group commit has published a map")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("joining a second group did not panic")
+		}
+	}()
+
+	Join(NewCommitGroup(), users)
+}
+
+func TestReadTx(t *testing.T) {
+	left, right := New[int, int](), New[int, int]()
+
+	g := NewCommitGroup()
+	Join(g, left)
+	Join(g, right)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 1; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+
+			left.Set(0, i)
+			right.Set(0, i)
+
+			if err := g.Commit(); err != nil {
+				t.Errorf("Commit(): %v", err)
+
+				return
+			}
+		}
+	}()
+
+	tx := g.NewReadTx()
+	defer tx.Close()
+
+	l, r := TxReader(tx, left), TxReader(tx, right)
+
+	for i := 0; i < 10000; i++ {
+		tx.Enter()
+
+		if lv, rv := l.Get(0), r.Get(0); lv != rv {
+			t.Fatalf("transaction sees a mix of generations: %d and %d", lv, rv)
+		}
+
+		tx.Leave()
+	}
+
+	close(stop)
+	<-done
+}
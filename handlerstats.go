@@ -0,0 +1,90 @@
+package lrmap
+
+import "time"
+
+type (
+	// HandlerStats counts the reads of a single read handler since NewReadHandler, see
+	// WithHandlerStats.
+	HandlerStats struct {
+		// Enters is the number of times the handler has been entered.
+		Enters uint64
+
+		// Gets is the number of lookups (Get, GetOK, Contains, GetMany per key), Misses the
+		// number of them that did not find the key.
+		Gets   uint64
+		Misses uint64
+
+		// Iterated is the number of entries visited by iterations.
+		Iterated uint64
+
+		// Entered is the total time the handler has been entered, MaxEntered the longest
+		// single entry.  Neither includes an entry that has not been left yet.
+		Entered    time.Duration
+		MaxEntered time.Duration
+	}
+
+	// handlerStats is the state of HandlerStats.  A handler is used by one goroutine at a
+	// time, so it needs no synchronization.
+	handlerStats struct {
+		HandlerStats
+		enteredAt time.Time
+	}
+)
+
+// WithHandlerStats makes every read handler count its reads, so that read load can be
+// attributed to the parts of a program (see ReadHandler.Stats), and handlers that stay entered
+// for long while reading little stand out.  It costs a clock reading per Enter and Leave.
+func WithHandlerStats[K comparable, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.handlerStats = true
+	}
+}
+
+// Stats returns the read statistics of the handler.  It requires WithHandlerStats.
+func (rh *ReadHandler[K, V]) Stats() HandlerStats {
+	rh.assertHandler()
+
+	if rh.inner.stats == nil {
+		panic("illegal use: Stats() requires WithHandlerStats()")
+	}
+
+	return rh.inner.stats.HandlerStats
+}
+
+func (r *readHandlerInner[K, V]) countEnter() {
+	if r.stats != nil {
+		r.stats.Enters++
+		r.stats.enteredAt = r.lrmap.clock.Now()
+	}
+}
+
+func (r *readHandlerInner[K, V]) countLeave() {
+	if r.stats != nil {
+		d := r.lrmap.clock.Now().Sub(r.stats.enteredAt)
+		r.stats.Entered += d
+		r.stats.MaxEntered = max(r.stats.MaxEntered, d)
+	}
+}
+
+func (r *readHandlerInner[K, V]) countGet(found bool) {
+	if r.stats != nil {
+		r.stats.Gets++
+
+		if !found {
+			r.stats.Misses++
+		}
+	}
+}
+
+// counted wraps fn to count the entries it visits.
+func (r *readHandlerInner[K, V]) counted(fn func(K, V) bool) func(K, V) bool {
+	if r.stats == nil {
+		return fn
+	}
+
+	return func(key K, value V) bool {
+		r.stats.Iterated++
+
+		return fn(key, value)
+	}
+}
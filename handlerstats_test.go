@@ -0,0 +1,54 @@
+package lrmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHandlerStats(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	lrm := New(WithHandlerStats[int, int](), WithClock[int, int](clock))
+
+	for i := 0; i < 10; i++ {
+		lrm.Set(i, i)
+	}
+
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+
+	rh.Enter()
+	rh.Get(1)
+	rh.Get(100)
+	rh.Contains(2)
+	rh.GetMany(3, 4, 200)
+	clock.now = clock.now.Add(time.Second)
+	rh.Leave()
+
+	rh.Enter()
+	rh.Iterate(func(k, _ int) bool { return k != 5 })
+	clock.now = clock.now.Add(2 * time.Second)
+	rh.Leave()
+
+	s := rh.Stats()
+
+	if s.Enters != 2 || s.Gets != 6 || s.Misses != 2 {
+		t.Errorf("want 2 enters, 6 gets, and 2 misses, got %+v", s)
+	}
+
+	if s.Iterated == 0 || s.Iterated > 10 {
+		t.Errorf("want 1 to 10 iterated entries, got %d", s.Iterated)
+	}
+
+	if s.Entered != 3*time.Second || s.MaxEntered != 2*time.Second {
+		t.Errorf("want 3s entered and 2s at most, got %v and %v", s.Entered, s.MaxEntered)
+	}
+
+	// a recycled handler starts over
+	rh.Recycle()
+	rh = lrm.NewReadHandler()
+
+	if s := rh.Stats(); s != (HandlerStats{}) {
+		t.Errorf("want zero stats for a new handler, got %+v", s)
+	}
+}
@@ -0,0 +1,86 @@
+package lrmap
+
+import (
+	"encoding/binary"
+	"hash/maphash"
+	"math"
+	"reflect"
+)
+
+// reflectHasher hashes keys by walking them with reflection, following the rules of == for
+// comparable types: it hashes the pointer of pointers and channels, the dynamic type and
+// value of interfaces, and all fields but the blank ones of structs, and it hashes -0 like +0.
+// It is the default hash function before Go 1.24 brought maphash.Comparable.
+func reflectHasher[K comparable]() func(K) uint64 {
+	seed := maphash.MakeSeed()
+
+	return func(key K) uint64 {
+		var h maphash.Hash
+
+		h.SetSeed(seed)
+		hashValue(&h, reflect.ValueOf(&key).Elem())
+
+		return h.Sum64()
+	}
+}
+
+func hashValue(h *maphash.Hash, v reflect.Value) {
+	var buf [8]byte
+
+	writeUint := func(u uint64) {
+		binary.LittleEndian.PutUint64(buf[:], u)
+		_, _ = h.Write(buf[:])
+	}
+
+	writeFloat := func(f float64) {
+		if f == 0 {
+			f = 0 // -0 == +0
+		}
+
+		writeUint(math.Float64bits(f))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			writeUint(1)
+		} else {
+			writeUint(0)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		writeUint(uint64(v.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		writeUint(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		writeFloat(v.Float())
+	case reflect.Complex64, reflect.Complex128:
+		writeFloat(real(v.Complex()))
+		writeFloat(imag(v.Complex()))
+	case reflect.String:
+		_, _ = h.WriteString(v.String())
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		writeUint(uint64(v.Pointer()))
+	case reflect.Interface:
+		if v.IsNil() {
+			writeUint(0)
+
+			return
+		}
+
+		_, _ = h.WriteString(v.Elem().Type().String())
+		hashValue(h, v.Elem())
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			hashValue(h, v.Index(i))
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Name != "_" {
+				hashValue(h, v.Field(i))
+			}
+		}
+	default:
+		// not comparable, so == would panic, too
+		panic("illegal use: cannot hash " + v.Type().String())
+	}
+}
@@ -0,0 +1,11 @@
+//go:build go1.24
+
+package lrmap
+
+import "hash/maphash"
+
+func defaultHasher[K comparable]() func(K) uint64 {
+	seed := maphash.MakeSeed()
+
+	return func(key K) uint64 { return maphash.Comparable(seed, key) }
+}
@@ -0,0 +1,5 @@
+//go:build !go1.24
+
+package lrmap
+
+func defaultHasher[K comparable]() func(K) uint64 { return reflectHasher[K]() }
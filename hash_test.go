@@ -0,0 +1,39 @@
+package lrmap
+
+import (
+	"math"
+	"testing"
+)
+
+func TestReflectHasher(t *testing.T) {
+	type key struct {
+		name string
+		f    float64
+		_    int
+		any  any
+		ptr  *int
+		arr  [2]int8
+	}
+
+	hash := reflectHasher[key]()
+	one, two := 1, 2
+
+	a := key{name: "a", f: 0, any: 1, ptr: &one, arr: [2]int8{1, 2}}
+	b := key{name: "a", f: math.Copysign(0, -1), any: 1, ptr: &one, arr: [2]int8{1, 2}}
+
+	if a != b || hash(a) != hash(b) {
+		t.Errorf("equal keys %+v and %+v hash differently", a, b)
+	}
+
+	for _, c := range []key{
+		{name: "b", f: 0, any: 1, ptr: &one, arr: [2]int8{1, 2}},
+		{name: "a", f: 1, any: 1, ptr: &one, arr: [2]int8{1, 2}},
+		{name: "a", f: 0, any: int64(1), ptr: &one, arr: [2]int8{1, 2}},
+		{name: "a", f: 0, any: 1, ptr: &two, arr: [2]int8{1, 2}},
+		{name: "a", f: 0, any: 1, ptr: &one, arr: [2]int8{2, 1}},
+	} {
+		if hash(a) == hash(c) {
+			t.Errorf("different keys %+v and %+v hash alike", a, c)
+		}
+	}
+}
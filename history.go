@@ -0,0 +1,126 @@
+package lrmap
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sync/atomic"
+)
+
+// ErrNotRetained is returned by ReadHandler.EnterAt for generations that are not retained.
+var ErrNotRetained = errors.New("generation not retained")
+
+// history keeps the arenas of former generations for readers that pin them (see WithHistory).
+type history[K comparable, V any] struct {
+	n int
+
+	// sides holds the retained arenas, oldest first.  The slice is replaced, never modified,
+	// so that readers may look up generations without taking the writer lock.
+	sides atomic.Pointer[[]*side[K, V]]
+}
+
+// WithHistory keeps the arenas of the n generations before the current one alive, so that
+// readers can deliberately look at an older state of the map, e.g. for long-running consistent
+// scans that must not hold off commits.  Retained arenas are never written again, and readers
+// of retained arenas do not hold off commits.
+//
+// Instead of replaying the redo log on the arena it takes back, each commit retires that arena
+// and copies the published one, so commits take time linear in the size of the map, and the
+// map holds up to n+2 copies of its entries.
+func WithHistory[K comparable, V any](n int) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.history.n = n
+	}
+}
+
+// RetainedGenerations returns the generations that are retained, oldest first, not including
+// the current one.
+func (m *LRMap[K, V]) RetainedGenerations() []uint64 {
+	sides := m.history.sides.Load()
+	if sides == nil {
+		return nil
+	}
+
+	gens := make([]uint64, len(*sides))
+	for i, s := range *sides {
+		gens[i] = s.gen
+	}
+
+	return gens
+}
+
+// retire moves the arena of the write map into the history, and replaces it by a copy of the
+// read map.  The caller must hold m.mu.
+func (m *LRMap[K, V]) retire() {
+	write := m.writeMap.Load()
+
+	// nolint:exhaustivestruct
+	retired := &side[K, V]{data: write.data, gen: write.gen, meta: write.meta}
+
+	var sides []*side[K, V]
+	if old := m.history.sides.Load(); old != nil {
+		sides = *old
+	}
+
+	sides = append(slices.Clip(sides[max(0, len(sides)+1-m.history.n):]), retired)
+	m.history.sides.Store(&sides)
+
+	m.rebuild()
+}
+
+// dropHistory releases all retained arenas.  The caller must hold m.mu.
+func (m *LRMap[K, V]) dropHistory() {
+	m.history.sides.Store(nil)
+}
+
+// EnterAt is like TryEnter, but enters the view of generation gen, which must be the current
+// generation or one retained by WithHistory.  Handlers entered into a retained generation do
+// not hold off commits.
+func (rh *ReadHandler[K, V]) EnterAt(gen uint64) error {
+	rh.assertReady()
+
+	return rh.inner.enterAt(gen)
+}
+
+// Generation returns the generation of the view the handler is entered into.
+func (rh *ReadHandler[K, V]) Generation() uint64 {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	return rh.inner.live.gen
+}
+
+func (r *readHandlerInner[K, V]) enterAt(gen uint64) error {
+	if err := r.tryEnter(); err != nil {
+		return err
+	}
+
+	if r.live.gen == gen {
+		return nil
+	}
+
+	// Entering first makes sure that a generation that has been current before is either
+	// still current or has been retired by now.
+	r.leave()
+
+	if sides := r.lrmap.history.sides.Load(); sides != nil {
+		for _, s := range *sides {
+			if s.gen == gen {
+				// retained arenas are never written again, so there is nothing to track
+				r.live = s
+				r.untracked = true
+
+				if r.stats != nil {
+					r.stats.enteredAt = r.lrmap.clock.Now()
+				}
+
+				return nil
+			}
+		}
+	}
+
+	return fmt.Errorf("%w: %d", ErrNotRetained, gen)
+}
@@ -0,0 +1,90 @@
+package lrmap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	lrm := New(WithHistory[string, int](2))
+
+	for i := 1; i <= 4; i++ {
+		lrm.Set("gen", i)
+		lrm.Commit()
+	}
+
+	if got, want := lrm.RetainedGenerations(), []uint64{2, 3}; !slices.Equal(got, want) {
+		t.Fatalf("RetainedGenerations(): want %v, got %v", want, got)
+	}
+
+	for _, s := range *lrm.history.sides.Load() {
+		if got, _ := s.data.Get("gen"); uint64(got) != s.gen {
+			t.Errorf("retained generation %d: want gen=%d, got %d", s.gen, s.gen, got)
+		}
+	}
+
+	// the rebuilt write arena is in sync
+	lrm.Set("next", 5)
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if got := rh.Get("gen"); got != 4 {
+		t.Errorf("Get(gen): want 4, got %d", got)
+	}
+
+	if got := rh.Len(); got != 2 {
+		t.Errorf("Len(): want 2, got %d", got)
+	}
+}
+
+func TestEnterAt(t *testing.T) {
+	lrm := New(WithHistory[string, int](1))
+
+	lrm.Set("a", 1)
+	lrm.Commit()
+	lrm.Set("a", 2)
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	if err := rh.EnterAt(1); err != nil {
+		t.Fatalf("EnterAt(1): %v", err)
+	}
+
+	// a reader of a retained generation does not hold off commits
+	lrm.Set("a", 3)
+	lrm.Commit()
+
+	if got, gen := rh.Get("a"), rh.Generation(); got != 1 || gen != 1 {
+		t.Errorf("EnterAt(1): want a=1 at generation 1, got a=%d at generation %d", got, gen)
+	}
+
+	rh.Leave()
+
+	if err := rh.EnterAt(3); err != nil {
+		t.Fatalf("EnterAt(3): %v", err)
+	}
+
+	if got := rh.Get("a"); got != 3 {
+		t.Errorf("EnterAt(3): want a=3, got %d", got)
+	}
+
+	rh.Leave()
+
+	for _, gen := range []uint64{1, 4} {
+		if err := rh.EnterAt(gen); !errors.Is(err, ErrNotRetained) {
+			t.Errorf("EnterAt(%d): want ErrNotRetained, got %v", gen, err)
+		}
+
+		if rh.inner.entered() {
+			t.Fatalf("EnterAt(%d) failed, but left the handler entered", gen)
+		}
+	}
+}
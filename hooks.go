@@ -0,0 +1,74 @@
+package lrmap
+
+import "fmt"
+
+// WithPreCommit registers a hook that is called with the pending operations before a commit
+// publishes them.  If the hook returns an error, the commit is aborted (see TryCommit).  The
+// hook is called with the writer lock held and must not call into the map.
+func WithPreCommit[K comparable, V any](fn func(pending []Op[K, V]) error) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.preCommit = append(m.preCommit, fn)
+	}
+}
+
+// WithPostCommit registers a hook that is called with the new generation after each commit.
+// The hook is called after the writer lock has been released.
+func WithPostCommit[K comparable, V any](fn func(gen uint64)) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.postCommit = append(m.postCommit, fn)
+	}
+}
+
+// WithValidator registers a function that checks entries before they are written to the map.
+// Entries for which it returns an error are rejected.
+func WithValidator[K comparable, V any](fn func(K, V) error) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.validator = fn
+	}
+}
+
+func (m *LRMap[K, V]) validate(key K, value V) error {
+	if m.validator == nil {
+		return nil
+	}
+
+	if err := m.validator(key, value); err != nil {
+		return fmt.Errorf("invalid entry for key %v: %w", key, err)
+	}
+
+	return nil
+}
+
+// WithReducer makes Set merge the new value of an existing key with the old one instead of
+// replacing it, e.g. to sum up or keep the maximum.  The merged value is what is validated and
+// what the redo log records, so replay just copies it and does not call fn again.
+func WithReducer[K comparable, V any](fn func(key K, old, new V) V) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.reducer = fn
+	}
+}
+
+// reduce returns what Set should store under key.  The caller must hold m.mu.
+func (m *LRMap[K, V]) reduce(key K, value V) V {
+	if m.reducer == nil {
+		return value
+	}
+
+	m.syncKey(key)
+
+	if old, ok := m.writeMap.Load().data.Get(key); ok {
+		return m.reducer(key, old, value)
+	}
+
+	return value
+}
+
+// WithValueCopier makes replay store a copy made by fn instead of the value itself, so that
+// the two arenas never share (the memory referenced by) a value, and the writer may modify the
+// values it gets from the write map without affecting readers.  The value passed to Set is
+// stored as is, so it must not be modified after Set.
+func WithValueCopier[K comparable, V any](fn func(V) V) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.copier = fn
+	}
+}
@@ -0,0 +1,186 @@
+package lrmap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestCommitHooks(t *testing.T) {
+	errVeto := errors.New("veto")
+
+	var (
+		veto      bool
+		seen      []Op[string, int]
+		published []uint64
+	)
+
+	lrm := New(
+		WithPreCommit(func(pending []Op[string, int]) error {
+			seen = pending
+			if veto {
+				return errVeto
+			}
+
+			return nil
+		}),
+		WithPostCommit[string, int](func(gen uint64) { published = append(published, gen) }),
+	)
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", 1)
+	lrm.Delete("b")
+
+	veto = true
+
+	if err := lrm.TryCommit(); !errors.Is(err, errVeto) {
+		t.Errorf("TryCommit(), want veto error, got %v", err)
+	}
+
+	if len(seen) != 2 || seen[0] != (Op[string, int]{Kind: OpSet, Key: "a", Value: 1}) || seen[1].Kind != OpDelete {
+		t.Errorf("pre-commit hook got unexpected pending operations %v", seen)
+	}
+
+	rh.Enter()
+	if rh.Contains("a") {
+		t.Errorf("vetoed commit has been published")
+	}
+	rh.Leave()
+
+	veto = false
+
+	if err := lrm.TryCommit(); err != nil {
+		t.Errorf("TryCommit(), want no error, got %v", err)
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+	if !rh.Contains("a") {
+		t.Errorf("commit has not been published")
+	}
+	rh.Leave()
+
+	if len(published) != 2 || published[0] != 1 || published[1] != 2 {
+		t.Errorf("post-commit hook, want generations [1 2], got %v", published)
+	}
+}
+
+func TestValidator(t *testing.T) {
+	errNegative := errors.New("negative")
+
+	lrm := New(WithValidator(func(_ string, v int) error {
+		if v < 0 {
+			return errNegative
+		}
+
+		return nil
+	}))
+
+	if err := lrm.TrySet("a", -1); !errors.Is(err, errNegative) {
+		t.Errorf("TrySet(a, -1), want validation error, got %v", err)
+	}
+
+	lrm.Set("b", -1)
+
+	if err := lrm.TrySet("c", 1); err != nil {
+		t.Errorf("TrySet(c, 1), want no error, got %v", err)
+	}
+
+	lrm.MapValues(func(_ string, v int) int { return -v })
+
+	if lrm.Contains("a") || lrm.Contains("b") || lrm.Get("c") != 1 || len(lrm.redoLog) != 1 {
+		t.Errorf("rejected entries have been written: %v", lrm)
+	}
+}
+
+func TestReducer(t *testing.T) {
+	lrm := New(
+		WithReducer(func(_ string, old, new int) int { return max(old, new) }),
+		WithRedoCompaction[string, int](),
+	)
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", 2)
+	lrm.Set("a", 1)
+	lrm.Commit()
+
+	lrm.Delete("a")
+	lrm.Set("a", 1)
+	lrm.Set("b", 3)
+	lrm.Set("b", 2)
+	lrm.Commit()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if a, b := rh.Get("a"), rh.Get("b"); a != 1 || b != 3 {
+		t.Errorf("Get(a), Get(b) = %d, %d, want 1, 3", a, b)
+	}
+}
+
+func TestValueCopier(t *testing.T) {
+	lrm := New(WithValueCopier[string, []int](slices.Clone[[]int]))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", []int{1})
+	lrm.Commit()
+	lrm.Commit()
+
+	// the write map now holds the replayed copy, so modifying it must not reach readers
+	lrm.Get("a")[0] = 2
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if v := rh.Get("a"); v[0] != 1 {
+		t.Errorf("reader sees a value modified by the writer: %v", v)
+	}
+}
+
+func TestModify(t *testing.T) {
+	lrm := New(WithValueCopier[string, []int](slices.Clone[[]int]))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", []int{1})
+	lrm.Commit()
+
+	rh.Enter()
+	published := rh.Get("a")
+	rh.Leave()
+
+	for i := 0; i < 2; i++ {
+		lrm.Modify("a", func(v []int) []int {
+			v[0]++
+
+			return v
+		})
+	}
+
+	lrm.Modify("b", func(v []int) []int { return append(v, 7) })
+
+	if published[0] != 1 {
+		t.Errorf("Modify() has modified the published value: %v", published)
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if v := rh.Get("a"); v[0] != 3 {
+		t.Errorf("Get(a) after Modify(), want [3], got %v", v)
+	}
+
+	if v := rh.Get("b"); !slices.Equal(v, []int{7}) {
+		t.Errorf("Get(b) after Modify() of a missing key, want [7], got %v", v)
+	}
+}
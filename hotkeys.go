@@ -0,0 +1,137 @@
+package lrmap
+
+import (
+	"cmp"
+	"slices"
+	"sync/atomic"
+)
+
+const (
+	// The count-min sketch of hot keys has hotKeyDepth rows of hotKeyWidth counters.
+	hotKeyDepth = 4
+	hotKeyWidth = 1024
+
+	// hotKeyCandidates is the number of keys tracked as candidates for the top keys.
+	hotKeyCandidates = 256
+)
+
+type (
+	// KeyCount is a key with its (estimated) number of reads.
+	KeyCount[K comparable] struct {
+		Key   K
+		Count uint64
+	}
+
+	// hotKeys samples the reads of read handlers.  Reads are counted in a count-min sketch,
+	// which never underestimates, and the most frequent keys are kept in a small table of
+	// candidates.  Both are updated with single atomic operations, so readers stay wait-free;
+	// a lost race merely drops a sample.
+	hotKeys[K comparable] struct {
+		every      uint64
+		hash       func(K) uint64
+		sketch     [hotKeyDepth][hotKeyWidth]atomic.Uint32
+		candidates [hotKeyCandidates]atomic.Pointer[hotKey[K]]
+	}
+
+	hotKey[K comparable] struct {
+		key   K
+		count atomic.Uint64
+	}
+)
+
+// WithHotKeys makes read handlers sample every n-th read (Get, GetOK, Contains) to find the
+// most frequently read keys, see TopKeys.  If hash is nil, a seeded hash of the comparable key
+// is used (requires Go 1.24).
+func WithHotKeys[K comparable, V any](n int, hash func(K) uint64) Option[K, V] {
+	if hash == nil {
+		hash = defaultHasher[K]()
+	}
+
+	return func(m *LRMap[K, V]) {
+		// nolint:exhaustivestruct
+		m.hotKeys = &hotKeys[K]{every: uint64(max(n, 1)), hash: hash}
+	}
+}
+
+// TopKeys returns the n most frequently read keys with their estimated number of reads since
+// the map has been created or ResetHotKeys has been called, most frequent first.  The
+// estimates are extrapolated from the samples and may be too high, but not too low.  It
+// requires WithHotKeys.
+func (m *LRMap[K, V]) TopKeys(n int) []KeyCount[K] {
+	if m.hotKeys == nil {
+		panic("illegal use: TopKeys() requires WithHotKeys()")
+	}
+
+	return m.hotKeys.top(n)
+}
+
+// ResetHotKeys starts counting reads for TopKeys anew.  It requires WithHotKeys.
+func (m *LRMap[K, V]) ResetHotKeys() {
+	if m.hotKeys == nil {
+		panic("illegal use: ResetHotKeys() requires WithHotKeys()")
+	}
+
+	m.hotKeys.reset()
+}
+
+// sample counts every n-th read of the handler.
+func (r *readHandlerInner[K, V]) sample(key K) {
+	if h := r.lrmap.hotKeys; h != nil {
+		if r.reads++; r.reads%h.every == 0 {
+			h.record(key)
+		}
+	}
+}
+
+func (h *hotKeys[K]) record(key K) {
+	hash := h.hash(key)
+
+	// derive the row hashes from the two halves of hash (Kirsch-Mitzenmacher)
+	h1, h2 := uint32(hash), uint32(hash>>32)|1
+
+	estimate := uint32(0)
+
+	for i := range h.sketch {
+		c := h.sketch[i][(h1+uint32(i)*h2)%hotKeyWidth].Add(1)
+		if i == 0 || c < estimate {
+			estimate = c
+		}
+	}
+
+	slot := &h.candidates[hash%hotKeyCandidates]
+
+	switch cand := slot.Load(); {
+	case cand == nil || cand.count.Load() < uint64(estimate) && cand.key != key:
+		hk := &hotKey[K]{key: key} // nolint:exhaustivestruct
+		hk.count.Store(uint64(estimate))
+		slot.CompareAndSwap(cand, hk)
+	case cand.key == key:
+		cand.count.Store(uint64(estimate))
+	}
+}
+
+func (h *hotKeys[K]) top(n int) []KeyCount[K] {
+	var top []KeyCount[K]
+
+	for i := range h.candidates {
+		if cand := h.candidates[i].Load(); cand != nil {
+			top = append(top, KeyCount[K]{Key: cand.key, Count: cand.count.Load() * h.every})
+		}
+	}
+
+	slices.SortFunc(top, func(a, b KeyCount[K]) int { return cmp.Compare(b.Count, a.Count) })
+
+	return top[:min(n, len(top))]
+}
+
+func (h *hotKeys[K]) reset() {
+	for i := range h.candidates {
+		h.candidates[i].Store(nil)
+	}
+
+	for i := range h.sketch {
+		for j := range h.sketch[i] {
+			h.sketch[i][j].Store(0)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package lrmap
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestTopKeys(t *testing.T) {
+	lrm := New(WithHotKeys[string, int](1, nil))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+
+	for i := 0; i < 1000; i++ {
+		_, _ = rh.GetOK("hot")
+		_ = rh.Get("hot")
+		_ = rh.Contains("warm")
+		_ = rh.Get("cold" + strconv.Itoa(i%100))
+	}
+
+	rh.Leave()
+
+	top := lrm.TopKeys(2)
+	if len(top) != 2 {
+		t.Fatalf("TopKeys(2): want 2 keys, got %v", top)
+	}
+
+	for i, want := range []KeyCount[string]{{Key: "hot", Count: 2000}, {Key: "warm", Count: 1000}} {
+		if got := top[i]; got.Key != want.Key || got.Count < want.Count*4/5 {
+			t.Errorf("TopKeys(2)[%d]: want about %v, got %v", i, want, got)
+		}
+	}
+
+	lrm.ResetHotKeys()
+
+	if top := lrm.TopKeys(1); len(top) != 0 {
+		t.Errorf("TopKeys() after ResetHotKeys(): want none, got %v", top)
+	}
+}
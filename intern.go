@@ -0,0 +1,49 @@
+package lrmap
+
+import "strings"
+
+// interner shares the backing data of equal keys, see WithKeyInterning.
+type interner[K comparable] struct {
+	keys  map[K]K
+	clone func(K) K
+}
+
+// WithKeyInterning makes the map intern the keys it stores: the first write of a key stores a
+// copy of it, and later writes of an equal key store that copy instead of their own, so that
+// both arenas and the redo log share a single copy of the key's bytes across generations.  It
+// also keeps keys that are substrings of large buffers (e.g. unmarshalled messages) from
+// retaining these buffers.
+//
+// The interner holds a reference to each key until it is deleted, which costs a map entry per
+// key; it pays off for keys longer than a few dozen bytes that are written repeatedly.
+func WithKeyInterning[K ~string, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.interner = &interner[K]{
+			keys:  make(map[K]K),
+			clone: func(key K) K { return K(strings.Clone(string(key))) },
+		}
+	}
+}
+
+// intern returns the interned copy of key.  The caller must hold m.mu.
+func (m *LRMap[K, V]) intern(key K) K {
+	if m.interner == nil {
+		return key
+	}
+
+	if interned, ok := m.interner.keys[key]; ok {
+		return interned
+	}
+
+	key = m.interner.clone(key)
+	m.interner.keys[key] = key
+
+	return key
+}
+
+// forget drops the interned copy of a deleted key.  The caller must hold m.mu.
+func (m *LRMap[K, V]) forget(key K) {
+	if m.interner != nil {
+		delete(m.interner.keys, key)
+	}
+}
@@ -0,0 +1,48 @@
+package lrmap
+
+import (
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+func TestKeyInterning(t *testing.T) {
+	lrm := New(WithKeyInterning[string, int]())
+
+	buf := strings.Repeat("x", 1024) + "key"
+	lrm.Set(buf[1024:], 1)
+	lrm.Commit()
+
+	// an equal key with its own backing data, as after unmarshalling
+	lrm.Set(strings.Clone("key"), 2)
+	lrm.Commit()
+	lrm.Commit()
+
+	var data *byte
+
+	for _, side := range []*side[string, int]{&lrm.left, &lrm.right} {
+		side.data.Iterate(func(key string, value int) bool {
+			if value != 2 {
+				t.Errorf("value of %q, want 2, got %d", key, value)
+			}
+
+			if data == nil {
+				data = unsafe.StringData(key)
+			} else if unsafe.StringData(key) != data {
+				t.Error("arenas hold distinct copies of the key")
+			}
+
+			return true
+		})
+	}
+
+	if data == unsafe.StringData(buf[1024:]) {
+		t.Error("interned key retains the buffer it has been cut from")
+	}
+
+	lrm.Delete("key")
+
+	if n := len(lrm.interner.keys); n != 0 {
+		t.Errorf("interner keeps %d keys after Delete", n)
+	}
+}
@@ -0,0 +1,58 @@
+package lrmap
+
+import (
+	"cmp"
+	"fmt"
+)
+
+type (
+	// Key2 is a composite key of two components, e.g. tenant and id.  Unlike keys built by
+	// concatenating strings, components cannot bleed into each other, and Key2 is compared
+	// field by field without allocating.
+	Key2[A, B comparable] struct {
+		A A
+		B B
+	}
+
+	// Key3 is a composite key of three components.
+	Key3[A, B, C comparable] struct {
+		A A
+		B B
+		C C
+	}
+)
+
+func MakeKey2[A, B comparable](a A, b B) Key2[A, B] { return Key2[A, B]{A: a, B: b} }
+
+func MakeKey3[A, B, C comparable](a A, b B, c C) Key3[A, B, C] {
+	return Key3[A, B, C]{A: a, B: b, C: c}
+}
+
+// String formats the key for debugging as (a, b).
+func (k Key2[A, B]) String() string { return fmt.Sprintf("(%v, %v)", k.A, k.B) }
+
+// String formats the key for debugging as (a, b, c).
+func (k Key3[A, B, C]) String() string { return fmt.Sprintf("(%v, %v, %v)", k.A, k.B, k.C) }
+
+// CompareKey2 orders keys by their first component, then by the second.  It can be passed
+// to WithOrderedArena to range over all keys sharing a first component.
+func CompareKey2[A, B cmp.Ordered](x, y Key2[A, B]) int {
+	if c := cmp.Compare(x.A, y.A); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(x.B, y.B)
+}
+
+// CompareKey3 orders keys lexicographically by their components.
+func CompareKey3[A, B, C cmp.Ordered](x, y Key3[A, B, C]) int {
+	if c := cmp.Compare(x.A, y.A); c != 0 {
+		return c
+	}
+
+	if c := cmp.Compare(x.B, y.B); c != 0 {
+		return c
+	}
+
+	return cmp.Compare(x.C, y.C)
+}
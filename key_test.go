@@ -0,0 +1,32 @@
+package lrmap
+
+import "testing"
+
+func TestCompositeKeys(t *testing.T) {
+	if s := MakeKey3("eu", 7, true).String(); s != "(eu, 7, true)" {
+		t.Errorf("String(), want (eu, 7, true), got %s", s)
+	}
+
+	for _, tc := range []struct {
+		x, y Key3[string, int, int]
+		want int
+	}{
+		{MakeKey3("a", 1, 1), MakeKey3("a", 1, 1), 0},
+		{MakeKey3("a", 1, 1), MakeKey3("b", 0, 0), -1},
+		{MakeKey3("a", 2, 0), MakeKey3("a", 1, 9), 1},
+		{MakeKey3("a", 1, 1), MakeKey3("a", 1, 2), -1},
+	} {
+		if c := CompareKey3(tc.x, tc.y); c != tc.want {
+			t.Errorf("CompareKey3(%s, %s), want %d, got %d", tc.x, tc.y, tc.want, c)
+		}
+	}
+
+	// Concatenating "a:" + "b:c" and "a:b" + ":c" collides, composite keys do not.
+	m := New[Key2[string, string], int]()
+	m.Set(MakeKey2("a:", "b:c"), 1)
+	m.Set(MakeKey2("a:b", ":c"), 2)
+
+	if v := m.Get(MakeKey2("a:", "b:c")); v != 1 {
+		t.Errorf("Get((a:, b:c)), want 1, got %d", v)
+	}
+}
@@ -0,0 +1,108 @@
+package lrmap
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrLoaderPanicked is returned by GetOrLoad to the callers that wait for a load whose loader
+// has panicked.  The panic goes on in the goroutine that called the loader.
+var ErrLoaderPanicked = errors.New("loader panicked")
+
+type loadCall[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// GetOrLoad returns the value of key from the committed view or, failing that, the write map.
+// If the key is in neither, loader is called to produce the value, which is then set (but not
+// committed).  Concurrent calls that miss the same key share a single call of loader, which
+// runs with the context of the first of them.
+func (m *LRMap[K, V]) GetOrLoad(
+	ctx context.Context,
+	key K,
+	loader func(context.Context, K) (V, error),
+) (V, error) {
+	rh := m.NewReadHandler()
+	defer rh.Recycle()
+
+	if err := rh.TryEnter(); err != nil {
+		var zero V
+
+		return zero, err
+	}
+
+	value, ok := rh.GetOK(key)
+	rh.Leave()
+
+	if ok {
+		return value, nil
+	}
+
+	key = m.normalize(key)
+
+	m.loadMu.Lock()
+
+	call, loading := m.loads[key]
+	if !loading {
+		// Check the write map while holding loadMu: a finished load stores its value before
+		// it unregisters, so there is no window in which the key would be loaded twice.
+		if value, ok := m.GetOK(key); ok {
+			m.loadMu.Unlock()
+
+			return value, nil
+		}
+
+		call = &loadCall[V]{done: make(chan struct{})} // nolint:exhaustivestruct
+
+		if m.loads == nil {
+			m.loads = make(map[K]*loadCall[V])
+		}
+
+		m.loads[key] = call
+	}
+
+	m.loadMu.Unlock()
+
+	if !loading {
+		m.load(ctx, key, call, loader)
+	}
+
+	select {
+	case <-call.done:
+		return call.value, call.err
+	case <-ctx.Done():
+		var zero V
+
+		return zero, ctx.Err()
+	}
+}
+
+func (m *LRMap[K, V]) load(
+	ctx context.Context,
+	key K,
+	call *loadCall[V],
+	loader func(context.Context, K) (V, error),
+) {
+	panicked := true
+
+	defer func() {
+		if panicked {
+			call.err = ErrLoaderPanicked
+		}
+
+		m.loadMu.Lock()
+		delete(m.loads, key)
+		m.loadMu.Unlock()
+
+		close(call.done)
+	}()
+
+	call.value, call.err = loader(ctx, key)
+	panicked = false
+
+	if call.err == nil {
+		call.err = m.TrySet(key, call.value)
+	}
+}
@@ -0,0 +1,136 @@
+package lrmap
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGetOrLoad(t *testing.T) {
+	lrm := New[int, string]()
+
+	lrm.Set(1, "committed")
+	lrm.Commit()
+	lrm.Set(2, "pending")
+
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	loader := func(_ context.Context, k int) (string, error) {
+		calls.Add(1)
+		<-release
+
+		if k < 0 {
+			return "", errors.New("negative")
+		}
+
+		return "loaded", nil
+	}
+
+	ctx := context.Background()
+
+	for k, want := range map[int]string{1: "committed", 2: "pending"} {
+		if v, err := lrm.GetOrLoad(ctx, k, loader); err != nil || v != want {
+			t.Errorf("GetOrLoad(%d), want (%s, nil), got (%s, %v)", k, want, v, err)
+		}
+	}
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if v, err := lrm.GetOrLoad(ctx, 3, loader); err != nil || v != "loaded" {
+				t.Errorf("GetOrLoad(3), want (loaded, nil), got (%s, %v)", v, err)
+			}
+		}()
+	}
+
+	for calls.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	close(release)
+	wg.Wait()
+
+	if n := calls.Load(); n != 1 {
+		t.Errorf("want 1 loader call, got %d", n)
+	}
+
+	if _, err := lrm.GetOrLoad(ctx, -1, loader); err == nil || lrm.Contains(-1) {
+		t.Errorf("GetOrLoad(-1), want loader error and no entry, got %v", err)
+	}
+}
+
+func TestGetOrLoadPanic(t *testing.T) {
+	lrm := New[int, int]()
+
+	var calls atomic.Int32
+
+	release := make(chan struct{})
+	loader := func(_ context.Context, k int) (int, error) {
+		if calls.Add(1) == 1 {
+			<-release
+			panic("boom")
+		}
+
+		return k, nil
+	}
+
+	panicked := make(chan any)
+
+	go func() {
+		defer func() { panicked <- recover() }()
+
+		_, _ = lrm.GetOrLoad(context.Background(), 1, loader)
+	}()
+
+	for calls.Load() == 0 {
+		runtime.Gosched()
+	}
+
+	waited := make(chan error)
+
+	go func() {
+		_, err := lrm.GetOrLoad(context.Background(), 1, loader)
+		waited <- err
+	}()
+
+	// let the second call join the pending load
+	time.Sleep(10 * time.Millisecond)
+
+	close(release)
+
+	if v := <-panicked; v != "boom" {
+		t.Errorf("loading GetOrLoad() panicked with %v, want boom", v)
+	}
+
+	if err := <-waited; !errors.Is(err, ErrLoaderPanicked) {
+		t.Errorf("waiting GetOrLoad(), want ErrLoaderPanicked, got %v", err)
+	}
+
+	if v, err := lrm.GetOrLoad(context.Background(), 1, loader); err != nil || v != 1 {
+		t.Errorf("GetOrLoad(1) after panic, want (1, nil), got (%d, %v)", v, err)
+	}
+}
+
+func TestGetOrLoadCanceled(t *testing.T) {
+	lrm := New[int, int]()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := lrm.GetOrLoad(ctx, 1, func(ctx context.Context, _ int) (int, error) {
+		return 0, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("GetOrLoad() with canceled context, want context.Canceled, got %v", err)
+	}
+}
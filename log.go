@@ -0,0 +1,141 @@
+package lrmap
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultLogWait is how long a commit may wait for readers before it logs a warning.
+	defaultLogWait = 100 * time.Millisecond
+
+	// defaultLogRedoOps is the length of the redo log at which the writer logs a warning.
+	defaultLogRedoOps = 1 << 20
+
+	// logInterval is the minimum time between two warnings of the same kind.  Warnings that
+	// are suppressed in between are counted in the next one.
+	logInterval = time.Minute
+)
+
+// logger emits rate-limited warnings.  A nil logger discards them.
+type logger struct {
+	log     *slog.Logger
+	wait    time.Duration
+	redoOps int
+
+	mu         sync.Mutex
+	last       map[string]time.Time
+	suppressed map[string]int
+}
+
+// WithLogger makes the map log warnings about conditions that usually precede trouble: commits
+// that wait long for readers (while they still wait), a redo log that keeps growing without
+// commits, and handlers that were garbage collected while entered.  It also makes the map
+// recover and log panics of post-commit hooks, instead of crashing after the commit.  Each kind
+// of warning is logged at most once a minute.  See WithLogThresholds for what counts as long.
+func WithLogger[K comparable, V any](l *slog.Logger) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.logger = &logger{
+			log:        l,
+			wait:       defaultLogWait,
+			redoOps:    defaultLogRedoOps,
+			last:       make(map[string]time.Time),
+			suppressed: make(map[string]int),
+		}
+	}
+}
+
+// WithLogThresholds sets how long a commit may wait for readers and how many operations the
+// redo log may hold before the map logs a warning (see WithLogger).  The defaults are 100ms
+// and 2^20 operations.  Pass it after WithLogger.
+func WithLogThresholds[K comparable, V any](wait time.Duration, redoOps int) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		if m.logger == nil {
+			panic("illegal use: WithLogThresholds() requires WithLogger()")
+		}
+
+		m.logger.wait, m.logger.redoOps = wait, redoOps
+	}
+}
+
+// warn logs msg, unless a warning of the same kind has been logged within the last
+// logInterval.
+func (l *logger) warn(clock Clock, kind, msg string, args ...any) {
+	if l == nil {
+		return
+	}
+
+	now := clock.Now()
+
+	l.mu.Lock()
+
+	if last, ok := l.last[kind]; ok && now.Sub(last) < logInterval {
+		l.suppressed[kind]++
+		l.mu.Unlock()
+
+		return
+	}
+
+	l.last[kind] = now
+	suppressed := l.suppressed[kind]
+	l.suppressed[kind] = 0
+
+	l.mu.Unlock()
+
+	if suppressed > 0 {
+		args = append(args, "suppressed", suppressed)
+	}
+
+	l.log.Warn(msg, args...)
+}
+
+// logWait is called while a commit waits for readers.  It reports whether it has logged.
+func (m *LRMap[K, V]) logWait(waited time.Duration, readers []enteredReader) bool {
+	if m.logger == nil || waited < m.logger.wait {
+		return false
+	}
+
+	labels := make([]string, 0, len(readers))
+	for _, r := range readers {
+		labels = append(labels, fmt.Sprintf("%d:%s", r.owner.ID, r.owner.Label))
+	}
+
+	m.logger.warn(m.clock, "wait", "lrmap: commit is waiting for readers",
+		"generation", m.generation, "waited", waited, "readers", labels)
+
+	return true
+}
+
+// logRedo is called after each write.  The caller must hold m.mu.
+func (m *LRMap[K, V]) logRedo() {
+	if m.logger != nil && len(m.redoLog) == m.logger.redoOps {
+		m.logger.warn(m.clock, "redo", "lrmap: redo log is growing without commits",
+			"generation", m.generation, "ops", len(m.redoLog))
+	}
+}
+
+// finalize is attached as cleanup to handlers that need not be closed explicitly.
+func (r *readHandlerInner[K, V]) finalize() {
+	if r.slot.epoch.Load()%2 == 1 {
+		r.lrmap.logger.warn(r.lrmap.clock, "leak", "lrmap: read handler was garbage collected while entered",
+			"id", r.slot.owner.Load().ID, "label", r.slot.owner.Load().Label)
+	}
+
+	r.close()
+}
+
+// runPostCommit calls fn and, with a logger, recovers and logs its panic.
+func (m *LRMap[K, V]) runPostCommit(fn func(uint64), gen uint64) {
+	if m.logger != nil {
+		defer func() {
+			if v := recover(); v != nil {
+				m.logger.warn(m.clock, "panic", "lrmap: post-commit hook panicked",
+					"generation", gen, "panic", v)
+			}
+		}()
+	}
+
+	fn(gen)
+}
@@ -0,0 +1,89 @@
+package lrmap
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	lrm := New(
+		WithClock[int, int](clock),
+		WithLogger[int, int](slog.New(slog.NewTextHandler(&buf, nil))),
+		WithLogThresholds[int, int](100*time.Millisecond, 3),
+		WithPostCommit[int, int](func(gen uint64) {
+			if gen == 2 {
+				panic("boom")
+			}
+		}),
+	)
+
+	warnings := func() []string {
+		defer buf.Reset()
+
+		return strings.FieldsFunc(buf.String(), func(r rune) bool { return r == '\n' })
+	}
+
+	for i := 0; i < 5; i++ {
+		lrm.Set(i, i)
+	}
+
+	if w := warnings(); len(w) != 1 || !strings.Contains(w[0], "redo log") || !strings.Contains(w[0], "ops=3") {
+		t.Errorf("want a single warning about the redo log, got %q", w)
+	}
+
+	lrm.Commit()
+
+	// the redo log crosses the threshold again within a minute
+	for i := 0; i < 3; i++ {
+		lrm.Set(i, i)
+	}
+
+	if w := warnings(); len(w) != 0 {
+		t.Errorf("want rate limited warnings, got %q", w)
+	}
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.SetLabel("slowpoke")
+	rh.Enter()
+
+	clock.sleep = func(now time.Time) {
+		if now.Sub(time.Unix(0, 0)) >= time.Second && rh.inner.entered() {
+			rh.Leave()
+		}
+	}
+
+	// the post-commit hook panics, which the logger recovers
+	lrm.Commit()
+
+	w := warnings()
+	if len(w) != 2 {
+		t.Fatalf("want warnings about the reader wait and the panic, got %q", w)
+	}
+
+	if !strings.Contains(w[0], "waiting for readers") || !strings.Contains(w[0], "slowpoke") {
+		t.Errorf("want a warning about the reader wait, got %q", w[0])
+	}
+
+	if !strings.Contains(w[1], "panicked") || !strings.Contains(w[1], "boom") {
+		t.Errorf("want a warning about the panic, got %q", w[1])
+	}
+
+	// a minute later, the suppressed warning is counted
+	clock.now = clock.now.Add(time.Minute)
+
+	for i := 0; i < 3; i++ {
+		lrm.Set(i, i)
+	}
+
+	if w := warnings(); len(w) != 1 || !strings.Contains(w[0], "suppressed=1") {
+		t.Errorf("want a warning with a suppressed count, got %q", w)
+	}
+}
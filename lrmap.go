@@ -1,8 +1,9 @@
 package lrmap
 
 import (
+	"context"
 	"fmt"
-	"runtime"
+	"runtime/debug"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -11,50 +12,349 @@ import (
 type (
 	LRMap[K comparable, V any] struct {
 		mu              sync.Mutex
-		left            arena[K, V]
-		right           arena[K, V]
-		readMap         atomic.Pointer[arena[K, V]]
-		writeMap        atomic.Pointer[arena[K, V]]
+		left            side[K, V]
+		right           side[K, V]
+		readMap         atomic.Pointer[side[K, V]]
+		writeMap        atomic.Pointer[side[K, V]]
 		redoLog         []operation[K, V]
-		readHandlers    map[*readHandlerInner[K, V]]struct{}
+		readHandlers    registry
 		readHandlerPool sync.Pool
+		newArena        func() Arena[K, V]
+		sizer           func(K, V) uint64
+		generation      uint64
+		diff            *differ[K, V]
+		preCommit       []func([]Op[K, V]) error
+		postCommit      []func(uint64)
+		validator       func(K, V) error
+		add             func(V, V) V
+		reducer         func(K, V, V) V
+		subs            map[*subscription[K, V]]struct{}
+		deliverMu       sync.Mutex
+		delivery        delivery[K, V]
+		persister       Persister[K, V]
+		restoring       bool
+		clock           Clock
+		readerBarrier   func(int)
+		copier          func(V) V
+		requireClone    bool
+		committing      []Op[K, V]
+		group           *CommitGroup
+		loadMu          sync.Mutex
+		loads           map[K]*loadCall[V]
+		replayChunk     int
+		backlog         map[K]operation[K, V]
+		redoIndex       map[K]int
+		redoLogRetain   int
+		handlerIDs      atomic.Uint64
+		lastWait        WaitReport
+		debug           bool
+		explicitClose   bool
+		freeHandlers    chan *ReadHandler[K, V]
+
+		// onMisuse replaces panicking on misuse detected in debug mode (for tests).
+		onMisuse func(msg string)
+
+		stragglerThreshold time.Duration
+		stragglerStacks    func(gen uint64, stacks []ReaderStack)
+
+		normalizer    func(K) K
+		rebuildFactor int
+		frozen        atomic.Bool
+		closed        atomic.Bool
+		watchdog      watchdog
+		history       history[K, V]
+		hasPrefix     func(key, prefix K) bool
+		budget        budget[K, V]
+		entryMeta     bool
+		hotKeys       *hotKeys[K]
+		tracer        Tracer
+		traceCtx      context.Context
+		logger        *logger
+		committedLen  atomic.Int64
+		async         atomic.Pointer[asyncWrite[K, V]]
+		pipeline      *pipeline[K, V]
+		pendingLimit  int
+		drained       chan struct{}
+		readerPool    *readerPool
+		handlerStats  bool
+		tombstones    bool
+		maxEnter      time.Duration
+		onOverrun     func(EnterOverrun)
+		highWater     int
+		rebuildNext   bool
+		interner      *interner[K]
 	}
 
-	arena[K comparable, V any] map[K]V
+	side[K comparable, V any] struct {
+		data Arena[K, V]
+
+		// sorted caches the ascending key order of data for ordered key types.  It is computed
+		// lazily by readers and reset by the writer once it has taken the arena back.
+		sorted atomic.Pointer[[]K]
+
+		// gen is the generation the arena has been published as.  It is written by the writer
+		// before publishing the arena, thus readers may read it once they have entered.
+		gen uint64
+
+		// meta holds the metadata of the entries of data, see WithEntryMeta.
+		meta map[K]EntryMeta
+	}
 )
 
-func New[K comparable, V any]() *LRMap[K, V] {
+func New[K comparable, V any](opts ...Option[K, V]) *LRMap[K, V] {
 	// nolint:exhaustivestruct
 	m := &LRMap[K, V]{
-		left:         make(arena[K, V]),
-		right:        make(arena[K, V]),
-		readHandlers: make(map[*readHandlerInner[K, V]]struct{}),
+		newArena:      NewMapArena[K, V],
+		redoLogRetain: defaultRedoLogRetain,
+		clock:         systemClock{},
+		rebuildFactor: defaultRebuildFactor,
+	}
+
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	// Without cleanups, handlers must be closed explicitly, and they must not be pooled in a
+	// sync.Pool, which drops them without closing them.
+	if !cleanupsSupported && !m.explicitClose {
+		WithExplicitClose[K, V]()(m)
+	}
+
+	if m.readerPool != nil {
+		m.freeHandlers = make(chan *ReadHandler[K, V], m.readerPool.max)
+	}
+
+	if m.copier == nil {
+		m.copier = cloner[V]()
+
+		if m.copier == nil && m.requireClone {
+			var zero V
+
+			// nolint:goerr113
+			panic(fmt.Errorf("illegal use: WithCloning requires %T to implement Cloner", zero))
+		}
+	}
+
+	m.left.data = m.newArena()
+	m.right.data = m.newArena()
+
+	if m.entryMeta {
+		m.left.meta = make(map[K]EntryMeta)
+		m.right.meta = make(map[K]EntryMeta)
 	}
 
 	m.readHandlerPool.New = func() interface{} { return m.newReadHandler() }
 
+	if m.readerPool != nil {
+		for i := 0; i < m.readerPool.min; i++ {
+			m.freeHandlers <- m.newReadHandler()
+		}
+	}
+
 	m.swap()
 
 	return m
 }
 
+// Set stores value under key.  If a validator rejects the entry, Set silently drops it; use
+// TrySet to learn about it.
 func (m *LRMap[K, V]) Set(key K, value V) {
+	_ = m.TrySet(key, value)
+}
+
+// TrySet is like Set, but returns the error of the validator if it rejects the entry.
+func (m *LRMap[K, V]) TrySet(key K, value V) error {
+	m.throttle()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	(*m.writeMap.Load())[key] = value
+	if m.deferring() {
+		return m.deferWrite(OpSet, key, value)
+	}
+
+	m.awaitCommit()
+
+	return m.trySet(key, value)
+}
+
+// trySet implements TrySet.  The caller must hold m.mu.
+func (m *LRMap[K, V]) trySet(key K, value V) error {
+	if err := m.writable(); err != nil {
+		return err
+	}
+
+	return m.admitSet(m.normalize(key), value)
+}
+
+// admitSet sets the normalized key unless the entry is rejected.  The caller must hold m.mu.
+func (m *LRMap[K, V]) admitSet(key K, value V) error {
+	value = m.reduce(key, value)
+
+	if err := m.validate(key, value); err != nil {
+		return err
+	}
+
+	if err := m.admit(key, value); err != nil {
+		return err
+	}
+
+	m.set(key, value)
+
+	return nil
+}
+
+// Swap is like Set, but returns the previous value of key, if any.  If the entry is rejected,
+// Swap silently drops it; use TrySwap to learn about it.
+func (m *LRMap[K, V]) Swap(key K, value V) (V, bool) {
+	previous, loaded, _ := m.TrySwap(key, value)
+
+	return previous, loaded
+}
+
+// TrySwap is like Swap, but returns the error if the entry is rejected.  The previous value is
+// returned either way.  Since it reads the write map, TrySwap waits for a pipelined commit
+// instead of deferring the write.
+func (m *LRMap[K, V]) TrySwap(key K, value V) (V, bool, error) {
+	m.throttle()
+	m.lock()
+	defer m.mu.Unlock()
+
+	key = m.normalize(key)
+	m.syncKey(key)
+
+	previous, loaded := m.writeMap.Load().data.Get(key)
 
-	m.redoLog = append(m.redoLog, operation[K, V]{typ: opSet, key: key, value: &value})
+	if err := m.writable(); err != nil {
+		return previous, loaded, err
+	}
+
+	return previous, loaded, m.admitSet(key, value)
 }
 
 func (m *LRMap[K, V]) Delete(key K) {
+	m.throttle()
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	delete(*(m.writeMap.Load()), key)
+	if m.deferring() {
+		var zero V
 
-	// nolint:exhaustivestruct
-	m.redoLog = append(m.redoLog, operation[K, V]{typ: opDelete, key: key})
+		_ = m.deferWrite(OpDelete, key, zero)
+
+		return
+	}
+
+	m.awaitCommit()
+
+	if m.writable() == nil {
+		m.delete(m.normalize(key))
+	}
+}
+
+// Pop deletes key and returns the value it had, if any.
+func (m *LRMap[K, V]) Pop(key K) (V, bool) {
+	m.lock()
+	defer m.mu.Unlock()
+
+	key = m.normalize(key)
+	m.syncKey(key)
+
+	value, ok := m.writeMap.Load().data.Get(key)
+	if ok && m.writable() == nil {
+		m.delete(key)
+	}
+
+	return value, ok
+}
+
+// DeleteFunc deletes all entries for which fn returns true and returns how many it deleted.
+func (m *LRMap[K, V]) DeleteFunc(fn func(K, V) bool) int {
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.writable() != nil {
+		return 0
+	}
+
+	m.syncAll()
+
+	// Arenas need not support deletion while iterating, so collect the keys first.
+	var keys []K
+
+	m.writeMap.Load().data.Iterate(func(key K, value V) bool {
+		if fn(key, value) {
+			keys = append(keys, key)
+		}
+
+		return true
+	})
+
+	for _, key := range keys {
+		m.delete(key)
+	}
+
+	return len(keys)
+}
+
+// MapValues replaces every value with the result of fn.  Results that a validator rejects are
+// dropped, i.e. the entry keeps its old value.
+func (m *LRMap[K, V]) MapValues(fn func(K, V) V) {
+	m.lock()
+	defer m.mu.Unlock()
+
+	type entry struct {
+		key   K
+		value V
+	}
+
+	if m.writable() != nil {
+		return
+	}
+
+	m.syncAll()
+
+	data := m.writeMap.Load().data
+	entries := make([]entry, 0, data.Len())
+
+	data.Iterate(func(key K, value V) bool {
+		entries = append(entries, entry{key: key, value: fn(key, value)})
+
+		return true
+	})
+
+	for _, e := range entries {
+		if m.validate(e.key, e.value) == nil {
+			m.set(e.key, e.value)
+		}
+	}
+}
+
+// Modify replaces the value of key with the result of fn, which gets a copy of the current value
+// made by the value copier (see WithValueCopier and Cloner), so that fn may modify it in place
+// while readers still see the current value.  Missing keys are passed as the zero value.
+// Without a copier, fn gets the current value itself, which it must not modify unless the value
+// type holds no references.  Results that a validator rejects are dropped.
+func (m *LRMap[K, V]) Modify(key K, fn func(copy V) V) {
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.writable() != nil {
+		return
+	}
+
+	key = m.normalize(key)
+	m.syncKey(key)
+
+	value, ok := m.writeMap.Load().data.Get(key)
+	if ok && m.copier != nil {
+		value = m.copier(value)
+	}
+
+	value = fn(value)
+
+	if m.validate(key, value) == nil && m.admit(key, value) == nil {
+		m.set(key, value)
+	}
 }
 
 func (m *LRMap[K, V]) Get(key K) V {
@@ -64,43 +364,311 @@ func (m *LRMap[K, V]) Get(key K) V {
 }
 
 func (m *LRMap[K, V]) GetOK(key K) (V, bool) {
-	m.mu.Lock()
+	m.lock()
 	defer m.mu.Unlock()
 
-	value, ok := (*m.writeMap.Load())[key]
+	key = m.normalize(key)
+	m.syncKey(key)
 
-	return value, ok
+	return m.writeMap.Load().data.Get(key)
 }
 
-func (m *LRMap[K, V]) Commit() {
-	m.mu.Lock()
+// set and delete operate on the write map and record the operation in the redo log. The
+// caller must hold m.mu.
+func (m *LRMap[K, V]) set(key K, value V) {
+	m.syncKey(key)
+
+	key = m.intern(key)
+
+	data := m.writeMap.Load().data
+	m.written(data, key, value, true)
+	data.Set(key, value)
+
+	m.log(operation[K, V]{typ: OpSet, key: key, value: value})
+}
+
+func (m *LRMap[K, V]) delete(key K) {
+	var zero V
+
+	m.syncKey(key)
+
+	data := m.writeMap.Load().data
+	m.written(data, key, zero, false)
+	data.Delete(key)
+
+	// nolint:exhaustivestruct
+	m.log(operation[K, V]{typ: OpDelete, key: key})
+}
+
+// written accounts for setting (or, unless set, deleting) key in data, which must be the write
+// map, right before the change is made.  The caller must hold m.mu.
+func (m *LRMap[K, V]) written(data Arena[K, V], key K, value V, set bool) {
+	m.charge(data, key, value, set)
+	m.stamp(key, set)
+
+	if !set {
+		m.forget(key)
+	}
+}
+
+// log appends op to the redo log, or, with compaction, replaces the previous operation on the
+// same key.
+func (m *LRMap[K, V]) log(op operation[K, V]) {
+	if m.redoIndex == nil {
+		m.redoLog = append(m.redoLog, op)
+	} else if i, ok := m.redoIndex[op.key]; ok {
+		m.redoLog[i] = m.merge(m.redoLog[i], op)
+	} else {
+		m.redoIndex[op.key] = len(m.redoLog)
+		m.redoLog = append(m.redoLog, op)
+	}
+
+	m.logRedo()
+	m.replaySome()
+}
+
+// GetMany returns the entries of all keys that exist in the write map.
+func (m *LRMap[K, V]) GetMany(keys ...K) map[K]V {
+	m.lock()
 	defer m.mu.Unlock()
 
+	keys = m.normalizeAll(keys)
+	for _, key := range keys {
+		m.syncKey(key)
+	}
+
+	return getMany(m.writeMap.Load().data, keys)
+}
+
+func (m *LRMap[K, V]) Contains(key K) bool {
+	m.lock()
+	defer m.mu.Unlock()
+
+	key = m.normalize(key)
+	m.syncKey(key)
+
+	return m.writeMap.Load().data.Contains(key)
+}
+
+func (m *LRMap[K, V]) Commit() { _ = m.TryCommit() }
+
+// TryCommit is like Commit, but returns the error of a pre-commit hook that vetoed the commit.
+// In that case, nothing is published and the pending operations are kept for the next commit.
+func (m *LRMap[K, V]) TryCommit() error { return m.CommitContext(context.Background()) }
+
+// commit publishes the write map and syncs the other arena.  The caller must hold m.mu.  The
+// phases are separate methods, so that a CommitGroup can interleave them for several maps.  If
+// pipelined is set, the commit releases m.mu while it waits for readers (see
+// WithPipelinedCommits).
+func (m *LRMap[K, V]) commit(pipelined bool) error {
+	if err := m.vetoCommit(); err != nil {
+		return err
+	}
+
+	if err := m.persistCommit(); err != nil {
+		return err
+	}
+
+	m.publish()
+
+	if pipelined {
+		m.finishPipelined()
+	} else {
+		m.finishCommit()
+	}
+
+	return nil
+}
+
+// vetoCommit runs the pre-commit hooks.
+func (m *LRMap[K, V]) vetoCommit() error {
+	m.committing = nil
+
+	if err := m.writable(); err != nil {
+		return err
+	}
+
+	m.drainAsync()
+
+	if len(m.preCommit) > 0 {
+		m.committing = m.pendingOps()
+
+		for _, fn := range m.preCommit {
+			if err := fn(m.committing); err != nil {
+				return fmt.Errorf("commit vetoed: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// persistCommit records the pending operations with the persister, if any.
+func (m *LRMap[K, V]) persistCommit() error {
+	if m.persister == nil || m.restoring {
+		return nil
+	}
+
+	if m.committing == nil {
+		m.committing = m.pendingOps()
+	}
+
+	batch := CommitBatch[K, V]{Generation: m.generation + 1, Ops: m.committing}
+	if err := m.persister.AppendOps(batch); err != nil {
+		return fmt.Errorf("commit not persisted: %w", err)
+	}
+
+	return nil
+}
+
+// publish makes the write map the new read map.
+func (m *LRMap[K, V]) publish() {
+	// the write map is about to be published, so it must be complete
+	m.syncAll()
+
+	m.generation++
+	m.writeMap.Load().gen = m.generation
+	n := m.writeMap.Load().data.Len()
+	m.committedLen.Store(int64(n))
+	m.highWater = max(m.highWater, n)
+
+	m.prepareDelivery(m.committing)
+	m.committing = nil
+
+	span := m.startSpan(SpanSwap)
 	m.swap()
+	span.End()
+}
 
-	m.waitForReaders()
+// finishCommit waits for the readers of the former read map and syncs it.
+func (m *LRMap[K, V]) finishCommit() {
+	m.lastWait = m.awaitStale()
+	m.syncStale()
+}
 
-	// redo all operations from the redo log into the new write map (old read map) to sync up.
-	for _, op := range m.redoLog {
-		switch op.typ {
-		case opSet:
-			(*(m.writeMap.Load()))[op.key] = *(op.value)
-		case opDelete:
-			delete(*(m.writeMap.Load()), op.key)
-		default:
-			// nolint:goerr113
-			panic(fmt.Errorf("operation(%d) not implemented", op.typ))
+// awaitStale waits for the readers of the former read map.
+func (m *LRMap[K, V]) awaitStale() WaitReport {
+	span := m.startSpan(SpanReaderWait)
+	defer span.End()
+
+	start := m.clock.Now()
+	stragglers := m.waitForReaders()
+	span.SetAttribute(AttrStragglers, int64(len(stragglers)))
+
+	return WaitReport{Generation: m.generation, Wait: m.clock.Now().Sub(start), Stragglers: stragglers}
+}
+
+// syncStale replays the redo log on the former read map, which no reader uses anymore.
+func (m *LRMap[K, V]) syncStale() {
+	m.writeMap.Load().sorted.Store(nil)
+
+	if m.diff != nil {
+		m.diff.last = m.diff.compute(m.writeMap.Load().data, m.readMap.Load().data, m.redoLog, m.hasPrefix)
+		m.diff.last.From, m.diff.last.To = m.generation-1, m.generation
+	}
+
+	span := m.startSpan(SpanReplay)
+	span.SetAttribute(AttrOps, int64(len(m.redoLog)))
+
+	switch {
+	case m.history.n > 0:
+		m.retire()
+	case m.shouldRebuild():
+		m.rebuild()
+	case m.replayChunk > 0 && len(m.redoLog) > m.replayChunk:
+		m.startReplay()
+	default:
+		// redo all operations from the redo log into the new write map (old read map) to sync up.
+		for _, op := range m.redoLog {
+			m.apply(op)
+		}
+	}
+
+	span.End()
+
+	m.rebuildNext = false
+
+	// Drop all references to stale keys and values, so the GC can remove them, but keep the
+	// backing array for the next round unless it has grown too large.
+	if cap(m.redoLog) <= m.redoLogRetain {
+		clear(m.redoLog)
+		m.redoLog = m.redoLog[:0]
+	} else {
+		m.redoLog = nil
+	}
+
+	clear(m.redoIndex)
+	m.notifyDrained()
+}
+
+// completeCommit releases m.mu, which the caller must hold, delivers the batch to subscribers,
+// and runs the post-commit hooks.
+func (m *LRMap[K, V]) completeCommit() {
+	gen := m.generation
+
+	// deliver releases m.mu
+	m.deliver()
+
+	for _, fn := range m.postCommit {
+		m.runPostCommit(fn, gen)
+	}
+}
+
+// apply replays op on the write map.
+func (m *LRMap[K, V]) apply(op operation[K, V]) {
+	switch op.typ {
+	case OpSet:
+		if m.copier != nil {
+			op.value = m.copier(op.value)
+		}
+
+		m.writeMap.Load().data.Set(op.key, op.value)
+	case OpDelete, OpPurge:
+		m.writeMap.Load().data.Delete(op.key)
+	case OpAdd:
+		data := m.writeMap.Load().data
+		old, _ := data.Get(op.key)
+		data.Set(op.key, m.add(old, op.value))
+	case OpDeletePrefix:
+		write := m.writeMap.Load()
+		for _, key := range m.prefixed(write.data, op.key) {
+			write.data.Delete(key)
+
+			if m.entryMeta {
+				m.syncMeta(key)
+			}
 		}
+
+		return
+	default:
+		// nolint:goerr113
+		panic(fmt.Errorf("operation(%d) not implemented", op.typ))
 	}
 
-	// drop the redo log completely and let the GC remove all references to stale keys and values
-	m.redoLog = nil
+	if m.entryMeta {
+		m.syncMeta(op.key)
+	}
 }
 
 func (m *LRMap[K, V]) NewReadHandler() *ReadHandler[K, V] {
-	rh := m.readHandlerPool.Get().(*ReadHandler[K, V])
+	var rh *ReadHandler[K, V]
+
+	if m.freeHandlers != nil {
+		select {
+		case rh = <-m.freeHandlers:
+		default:
+			rh = m.newReadHandler()
+		}
+	} else {
+		rh = m.readHandlerPool.Get().(*ReadHandler[K, V])
+	}
+
 	rh.ready = true
 
+	if m.handlerStats {
+		rh.inner.stats = new(handlerStats)
+	}
+
 	return rh
 }
 
@@ -108,27 +676,30 @@ func (m *LRMap[K, V]) newReadHandler() *ReadHandler[K, V] {
 	// Wrap the actual (inner) readHandler in an outer shim and return that shim to the
 	// user and only keep a "weak reference" to the inner readHandler, but not the shim.
 	// If the user drops any references to the outer shim, (eventually) the runtime runs
-	// the finalizer on that shim, so we can call close() on the inner read handler, i.e.
-	// remove the weak reference, enabling the GC to remove the actual read handler as well.
+	// the cleanup attached to that shim, so we can call close() on the inner read handler,
+	// i.e. remove the weak reference, enabling the GC to remove the actual read handler as
+	// well.
 	//
 	// The reason for doing so is that we can make the user's call to Close() optional.
 	// Calling it is still better, as it makes the resources free to remove for the GC
 	// immediately.  But if the user chooses to use resource management like `sync.Pool` or
 	// possibly other solutions like free-lists, the user may not be able to call Close(),
-	// thus leaking resources through the readHandlers list.
+	// thus leaking resources through the readHandlers registry.
 
+	// The registry does not take m.mu, so that handlers can be created while a commit holds
+	// it for waiting on readers.
 	// nolint:exhaustivestruct
-	inner := &readHandlerInner[K, V]{lrmap: m}
+	inner := &readHandlerInner[K, V]{lrmap: m, slot: m.readHandlers.acquire()}
+	inner.slot.owner.Store(&ReaderInfo{ID: m.handlerIDs.Add(1)}) // nolint:exhaustivestruct
 
-	m.mu.Lock()
-	m.readHandlers[inner] = struct{}{}
-	m.mu.Unlock()
+	outer := &ReadHandler[K, V]{inner: inner} // nolint:exhaustivestruct
 
-	outer := &ReadHandler[K, V]{inner: inner}
-	runtime.SetFinalizer(outer, func(rh *ReadHandler[K, V]) {
-		rh.ready = false
-		rh.inner.close()
-	})
+	switch {
+	case !m.explicitClose:
+		outer.cleanup = addCleanup(outer, (*readHandlerInner[K, V]).finalize, inner)
+	case m.debug:
+		outer.cleanup = addCleanup(outer, m.leaked, debug.Stack())
+	}
 
 	return outer
 }
@@ -147,62 +718,139 @@ func (m *LRMap[K, V]) swap() {
 	}
 }
 
-func (m *LRMap[K, V]) waitForReaders() {
-	readers := make(map[*readHandlerInner[K, V]]uint64)
+// waitForReaders waits until all readers that were entered at the time of the call have left,
+// and returns how long each of them kept the writer waiting.
+func (m *LRMap[K, V]) waitForReaders() []Straggler {
+	start := m.clock.Now()
 
-	for rh := range m.readHandlers {
-		if epoch := atomic.LoadUint64(&(rh.epoch)); epoch%2 == 1 {
-			readers[rh] = epoch
-		}
+	// Handlers that register after this snapshot cannot have entered the stale arena, since
+	// the swap has already happened.
+	readers := m.watchdog.skipExpired(m.enteredReaders())
+
+	if m.readerBarrier != nil {
+		m.readerBarrier(len(readers))
 	}
 
-	delay := time.Microsecond
+	var (
+		stragglers []Straggler
+		reported   bool
+		logged     bool
+		watched    func([]enteredReader) []enteredReader
+	)
 
-	for {
-		for reader, epoch := range readers {
-			if e := atomic.LoadUint64(&(reader.epoch)); e != epoch {
-				delete(readers, reader)
-			}
-		}
+	if m.watchdog.fn != nil {
+		watched = m.watchdog.watch(m.generation, start, m.clock)
+	}
 
-		if len(readers) == 0 {
-			return
+	waiting := func(readers []enteredReader) []enteredReader {
+		if m.debug && m.stragglerStacks != nil && !reported && m.clock.Now().Sub(start) >= m.stragglerThreshold {
+			reported = true
+			m.stragglerStacks(m.generation, readerStacks(readers))
 		}
 
-		time.Sleep(delay)
+		if !logged {
+			logged = m.logWait(m.clock.Now().Sub(start), readers)
+		}
 
-		const maxDelay = 5 * time.Second
+		for _, r := range readers {
+			m.checkEnterDuration(r.slot.enteredBy.Load(), r.owner)
+		}
 
-		delay *= 10
-		if delay > maxDelay {
-			delay = maxDelay
+		if watched != nil {
+			readers = watched(readers)
 		}
+
+		return readers
+	}
+
+	maxDelay := maxWaitDelay
+	if m.debug && m.maxEnter > 0 {
+		maxDelay = min(maxDelay, m.maxEnter)
 	}
-}
 
-type opType int8
+	_ = m.awaitReaders(context.Background(), readers, maxDelay, func(r enteredReader) {
+		stragglers = append(stragglers, Straggler{ReaderInfo: *r.owner, Wait: m.clock.Now().Sub(start)})
+	}, waiting)
 
-const (
-	opSet opType = iota
-	opDelete
-)
+	return stragglers
+}
 
+// operation is a record of the redo log.  The value is stored inline, so that recording a
+// write does not allocate (as long as the log has capacity left); it is the zero value for
+// deletions.
 type operation[K comparable, V any] struct {
-	typ   opType
+	typ   OpKind
 	key   K
-	value *V
+	value V
 }
 
 type ReadHandler[K comparable, V any] struct {
-	inner *readHandlerInner[K, V]
-	ready bool
+	inner   *readHandlerInner[K, V]
+	ready   bool
+	cleanup cleanup
 }
 
 func (rh *ReadHandler[K, V]) Enter()                { rh.assertReady(); rh.inner.enter() }
-func (rh *ReadHandler[K, V]) Leave()                { rh.assertReady(); rh.inner.leave() }
+func (rh *ReadHandler[K, V]) Leave()                { rh.assertHandler(); rh.inner.leave() }
 func (rh *ReadHandler[K, V]) Get(key K) V           { rh.assertReady(); return rh.inner.get(key) }
 func (rh *ReadHandler[K, V]) GetOK(key K) (V, bool) { rh.assertReady(); return rh.inner.getOK(key) }
 func (rh *ReadHandler[K, V]) Len() int              { rh.assertReady(); return rh.inner.len() }
+func (rh *ReadHandler[K, V]) Contains(key K) bool   { rh.assertReady(); return rh.inner.contains(key) }
+
+// TryEnter is like Enter, but returns ErrClosed instead of panicking if the map has been
+// closed.
+func (rh *ReadHandler[K, V]) TryEnter() error {
+	rh.assertReady()
+
+	return rh.inner.tryEnter()
+}
+
+// EnterContext is like TryEnter, but also fails with the error of ctx if it is done, so that
+// request scoped readers stop reading once their request has been canceled.
+func (rh *ReadHandler[K, V]) EnterContext(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return rh.TryEnter()
+}
+
+// Entered reports whether the handler is entered.  Unlike the other methods, it does not panic
+// on a handler that has been closed or recycled, but returns false.
+func (rh *ReadHandler[K, V]) Entered() bool {
+	return rh.inner != nil && rh.ready && rh.inner.entered()
+}
+
+// EpochCount returns the epoch of the handler, which Enter and Leave increment, so it is odd
+// while the handler is entered, and the difference of two readings tells how often the handler
+// has been entered in between.  Epochs carry over when handlers are recycled, so they do not
+// start at zero.  Entering a frozen map does not touch the epoch.  Like Entered, it does not
+// panic on a closed or recycled handler, but returns 0.
+func (rh *ReadHandler[K, V]) EpochCount() uint64 {
+	if rh.inner == nil || !rh.ready {
+		return 0
+	}
+
+	return rh.inner.slot.epoch.Load()
+}
+
+// AppendKeys appends all keys of the live view to dst and returns the extended slice.  With the
+// default MapArena it does not allocate if dst has enough capacity.
+func (rh *ReadHandler[K, V]) AppendKeys(dst []K) []K {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must call Enter() before iterating")
+	}
+
+	return appendKeys(dst, rh.inner.live.data)
+}
+
+func (rh *ReadHandler[K, V]) GetMany(keys ...K) map[K]V {
+	rh.assertReady()
+
+	return rh.inner.getMany(keys)
+}
 
 func (rh *ReadHandler[K, V]) Iterate(fn func(_ K, _ V) bool) {
 	rh.assertReady()
@@ -211,17 +859,58 @@ func (rh *ReadHandler[K, V]) Iterate(fn func(_ K, _ V) bool) {
 		panic("reader illegal state: must call Enter() before iterating")
 	}
 
-	for key, value := range rh.inner.live {
-		if ok := fn(key, value); !ok {
-			return
-		}
+	rh.inner.live.data.Iterate(rh.inner.counted(fn))
+}
+
+// Range calls fn for all entries with keys in [from, to) in ascending order until fn returns
+// false.  It requires an OrderedArena, see WithOrderedArena.
+func (rh *ReadHandler[K, V]) Range(from, to K, fn func(_ K, _ V) bool) {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must call Enter() before iterating")
+	}
+
+	ordered, ok := rh.inner.live.data.(OrderedArena[K, V])
+	if !ok {
+		panic("illegal use: Range() requires an ordered arena")
+	}
+
+	ordered.Range(from, to, rh.inner.counted(fn))
+}
+
+// RangeDesc is like Range, but visits the entries with keys in [from, to) in descending order.
+// It requires a DescendingArena, see WithOrderedArena.
+func (rh *ReadHandler[K, V]) RangeDesc(from, to K, fn func(_ K, _ V) bool) {
+	rh.descending("RangeDesc").RangeDesc(from, to, rh.inner.counted(fn))
+}
+
+// IterateDesc calls fn for all entries in descending key order until fn returns false, so the
+// greatest keys can be read without visiting the entire map.  It requires a DescendingArena,
+// see WithOrderedArena.
+func (rh *ReadHandler[K, V]) IterateDesc(fn func(_ K, _ V) bool) {
+	rh.descending("IterateDesc").IterateDesc(rh.inner.counted(fn))
+}
+
+func (rh *ReadHandler[K, V]) descending(method string) DescendingArena[K, V] {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must call Enter() before iterating")
 	}
+
+	arena, ok := rh.inner.live.data.(DescendingArena[K, V])
+	if !ok {
+		panic("illegal use: " + method + "() requires a descending arena")
+	}
+
+	return arena
 }
 
 func (rh *ReadHandler[K, V]) Close() {
-	rh.assertReady()
+	rh.assertHandler()
 
-	runtime.SetFinalizer(rh, nil)
+	stopCleanup(rh, rh.cleanup)
 	rh.ready = false
 	rh.inner.close()
 }
@@ -237,10 +926,41 @@ func (rh *ReadHandler[K, V]) Recycle() {
 
 	rh.ready = false
 
+	if m := rh.inner.lrmap; m.freeHandlers != nil {
+		if m.closed.Load() {
+			stopCleanup(rh, rh.cleanup)
+			rh.inner.close()
+
+			return
+		}
+
+		select {
+		case m.freeHandlers <- rh:
+		default:
+			stopCleanup(rh, rh.cleanup)
+			rh.inner.close()
+		}
+
+		return
+	}
+
 	rh.inner.lrmap.readHandlerPool.Put(rh)
 }
 
+// assertReady asserts that the handler may be used.
 func (rh *ReadHandler[K, V]) assertReady() {
+	rh.assertHandler()
+
+	if epoch := rh.inner.slot.abandoned.Load(); epoch != 0 && epoch == rh.inner.slot.epoch.Load() {
+		// nolint:goerr113
+		panic(fmt.Errorf("reader illegal state: handler %d (label %q) kept a commit waiting past the "+
+			"deadline of the watchdog and has been abandoned; it must Leave() before reading again",
+			rh.inner.slot.owner.Load().ID, rh.inner.slot.owner.Load().Label))
+	}
+}
+
+// assertHandler asserts that the handler may be left or closed.
+func (rh *ReadHandler[K, V]) assertHandler() {
 	if rh.inner == nil {
 		panic("reader illegal state: must create with NewReadHandler()")
 	}
@@ -248,21 +968,69 @@ func (rh *ReadHandler[K, V]) assertReady() {
 	if !rh.ready {
 		panic(fmt.Errorf("reader illegal state: must not use after Recycle()"))
 	}
+
+	rh.inner.checkGoroutine()
 }
 
 type readHandlerInner[K comparable, V any] struct {
-	lrmap *LRMap[K, V]
-	live  arena[K, V]
-	epoch uint64
+	lrmap     *LRMap[K, V]
+	live      *side[K, V]
+	slot      *epochSlot
+	enteredBy *enteredBy
+
+	// untracked is set while the handler is entered into a frozen map, which it enters
+	// without bumping its epoch, since there is no writer left to wait for it.
+	untracked bool
+
+	// reads counts the reads of the handler for sampling, see WithHotKeys.
+	reads uint64
+
+	// stats is set for maps WithHandlerStats.
+	stats *handlerStats
 }
 
 func (r *readHandlerInner[K, V]) enter() {
+	if err := r.tryEnter(); err != nil {
+		panic(fmt.Errorf("reader illegal state: %w", err))
+	}
+}
+
+// tryEnter enters the handler, unless the map has been closed.
+func (r *readHandlerInner[K, V]) tryEnter() error {
 	if r.entered() {
 		panic("reader illegal state: must not Enter() twice")
 	}
 
-	atomic.AddUint64(&r.epoch, 1)
-	r.live = *r.lrmap.readMap.Load()
+	// Record the goroutine before the epoch turns odd, so that a writer never sees an entered
+	// slot with the goroutine of a former entry (see checkSelfWait).
+	r.recordEnter()
+
+	if r.lrmap.frozen.Load() {
+		if r.lrmap.closed.Load() {
+			return ErrClosed
+		}
+
+		r.live = r.lrmap.readMap.Load()
+		r.untracked = true
+		r.countEnter()
+
+		return nil
+	}
+
+	r.slot.epoch.Add(1)
+
+	// Close sets the flag before it looks for entered readers, so either Close waits for this
+	// reader, or the reader sees the flag.
+	if r.lrmap.closed.Load() {
+		r.slot.epoch.Add(1)
+
+		return ErrClosed
+	}
+
+	r.live = r.lrmap.readMap.Load()
+	r.countEnter()
+
+	return nil
 }
 
 func (r *readHandlerInner[K, V]) leave() {
@@ -270,7 +1038,16 @@ func (r *readHandlerInner[K, V]) leave() {
 		panic("reader illegal state: must not Leave() twice")
 	}
 
-	atomic.AddUint64(&r.epoch, 1)
+	r.countLeave()
+	r.lrmap.checkEnterDuration(r.enteredBy, r.slot.owner.Load())
+
+	if r.untracked {
+		r.untracked = false
+
+		return
+	}
+
+	r.slot.epoch.Add(1)
 }
 
 func (r *readHandlerInner[K, V]) get(key K) V {
@@ -284,26 +1061,92 @@ func (r *readHandlerInner[K, V]) getOK(key K) (V, bool) {
 		panic("reader illegal state: must Enter() before operating on data")
 	}
 
-	value, ok := r.live[key]
+	key = r.lrmap.normalize(key)
+	r.sample(key)
+
+	value, ok := r.live.data.Get(key)
+	r.countGet(ok)
 
 	return value, ok
 }
 
+func (r *readHandlerInner[K, V]) contains(key K) bool {
+	if !r.entered() {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	key = r.lrmap.normalize(key)
+	r.sample(key)
+
+	ok := r.live.data.Contains(key)
+	r.countGet(ok)
+
+	return ok
+}
+
+func (r *readHandlerInner[K, V]) getMany(keys []K) map[K]V {
+	if !r.entered() {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	found := getMany(r.live.data, r.lrmap.normalizeAll(keys))
+
+	if r.stats != nil {
+		r.stats.Gets += uint64(len(keys))
+		r.stats.Misses += uint64(len(keys) - len(found))
+	}
+
+	return found
+}
+
 func (r *readHandlerInner[K, V]) len() int {
 	if !r.entered() {
 		panic("reader illegal state: must Enter() before operation on data")
 	}
 
-	return len(r.live)
+	return r.live.data.Len()
 }
 
 func (r *readHandlerInner[K, V]) close() {
-	r.lrmap.mu.Lock()
-	defer r.lrmap.mu.Unlock()
-
-	delete(r.lrmap.readHandlers, r)
+	r.lrmap.readHandlers.release(r.slot)
 }
 
 func (r *readHandlerInner[K, V]) entered() bool {
-	return r.epoch%2 == 1
+	return r.untracked || r.slot.epoch.Load()%2 == 1
+}
+
+func getMany[K comparable, V any](data Arena[K, V], keys []K) map[K]V {
+	found := make(map[K]V, len(keys))
+
+	for _, key := range keys {
+		if value, ok := data.Get(key); ok {
+			found[key] = value
+		}
+	}
+
+	return found
+}
+
+func appendKeys[K comparable, V any](dst []K, data Arena[K, V]) []K {
+	// Iterating through the interface makes the closure and dst escape, so range over maps
+	// directly.
+	if data, ok := data.(MapArena[K, V]); ok {
+		for key := range data {
+			dst = append(dst, key)
+		}
+
+		return dst
+	}
+
+	return appendKeysIterate(dst, data)
+}
+
+func appendKeysIterate[K comparable, V any](dst []K, data Arena[K, V]) []K {
+	data.Iterate(func(key K, _ V) bool {
+		dst = append(dst, key)
+
+		return true
+	})
+
+	return dst
 }
@@ -1,7 +1,9 @@
 package lrmap
 
 import (
+	"errors"
 	"math"
+	"runtime"
 	"testing"
 )
 
@@ -128,3 +130,279 @@ func TestEpochOverflow(t *testing.T) {
 		t.Errorf("overflow(%d) is odd", overflow)
 	}
 }
+
+func TestSwap(t *testing.T) {
+	lrm := New[int, string]()
+
+	if v, ok := lrm.Swap(1, "one"); ok || v != "" {
+		t.Errorf("Swap(1, one), want (\"\", false), got (%q, %t)", v, ok)
+	}
+
+	if v, ok := lrm.Swap(1, "uno"); !ok || v != "one" {
+		t.Errorf("Swap(1, uno), want (one, true), got (%q, %t)", v, ok)
+	}
+
+	if v := lrm.Get(1); v != "uno" {
+		t.Errorf("Get(1), want uno, got %q", v)
+	}
+
+	lrm = New(WithMemoryBudget[int, string](1, nil))
+
+	if v, ok, err := lrm.TrySwap(1, "one"); !errors.Is(err, ErrOverBudget) || ok || v != "" {
+		t.Errorf("TrySwap(1, one), want ErrOverBudget, got (%q, %t, %v)", v, ok, err)
+	}
+
+	if lrm.Contains(1) {
+		t.Error("TrySwap(1, one) over budget has set the entry")
+	}
+}
+
+func TestPop(t *testing.T) {
+	lrm := New[int, string]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set(1, "one")
+	lrm.Commit()
+
+	if v, ok := lrm.Pop(1); !ok || v != "one" {
+		t.Errorf("Pop(1), want (one, true), got (%q, %t)", v, ok)
+	}
+
+	if v, ok := lrm.Pop(1); ok || v != "" {
+		t.Errorf("second Pop(1), want (\"\", false), got (%q, %t)", v, ok)
+	}
+
+	if n := len(lrm.redoLog); n != 1 {
+		t.Errorf("want 1 operation in the redo log, got %d", n)
+	}
+
+	rh.Enter()
+	if _, ok := rh.GetOK(1); !ok {
+		t.Errorf("reader lost key 1 before Commit")
+	}
+	rh.Leave()
+
+	lrm.Commit()
+
+	rh.Enter()
+	if _, ok := rh.GetOK(1); ok {
+		t.Errorf("reader still sees key 1 after Commit")
+	}
+	rh.Leave()
+}
+
+func TestDeleteFunc(t *testing.T) {
+	lrm := New(WithOrderedArena[int, int](func(a, b int) int { return a - b }))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	for i := 0; i < 100; i++ {
+		lrm.Set(i, i)
+	}
+
+	if n := lrm.DeleteFunc(func(_ int, v int) bool { return v%3 == 0 }); n != 34 {
+		t.Errorf("DeleteFunc(), want 34 deletions, got %d", n)
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+	for i := 0; i < 100; i++ {
+		if _, ok := rh.GetOK(i); ok == (i%3 == 0) {
+			t.Errorf("GetOK(%d), want %t, got %t", i, i%3 != 0, ok)
+		}
+	}
+	rh.Leave()
+}
+
+func TestMapValues(t *testing.T) {
+	lrm := New[string, int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", 1)
+	lrm.Set("b", 2)
+	lrm.Commit()
+
+	lrm.MapValues(func(_ string, v int) int { return v * 10 })
+
+	if v := lrm.Get("b"); v != 20 {
+		t.Errorf("writer Get(b), want 20, got %d", v)
+	}
+
+	lrm.Commit()
+	lrm.Commit()
+
+	rh.Enter()
+	if a, b := rh.Get("a"), rh.Get("b"); a != 10 || b != 20 {
+		t.Errorf("reader want (10, 20), got (%d, %d)", a, b)
+	}
+	rh.Leave()
+}
+
+func TestGetMany(t *testing.T) {
+	lrm := New[int, int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	for i := 0; i < 10; i++ {
+		lrm.Set(i, i*i)
+	}
+
+	if got := lrm.GetMany(3, 42); len(got) != 1 || got[3] != 9 {
+		t.Errorf("writer GetMany(3, 42), want map[3:9], got %v", got)
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+	got := rh.GetMany(1, 2, 3, 11)
+	rh.Leave()
+
+	if len(got) != 3 || got[1] != 1 || got[2] != 4 || got[3] != 9 {
+		t.Errorf("GetMany(1, 2, 3, 11), want map[1:1 2:4 3:9], got %v", got)
+	}
+}
+
+func TestContains(t *testing.T) {
+	for name, opt := range map[string]Option[int, [64]byte]{
+		"map":   WithArena(NewMapArena[int, [64]byte]),
+		"btree": WithOrderedArena[int, [64]byte](func(a, b int) int { return a - b }),
+		"swiss": WithSwissArena[int, [64]byte](func(k int) uint64 { return uint64(k) }),
+	} {
+		lrm := New(opt)
+		rh := lrm.NewReadHandler()
+
+		lrm.Set(1, [64]byte{})
+		lrm.Commit()
+
+		if !lrm.Contains(1) || lrm.Contains(2) {
+			t.Errorf("%s: writer Contains(1), Contains(2), want (true, false)", name)
+		}
+
+		rh.Enter()
+		if !rh.Contains(1) || rh.Contains(2) {
+			t.Errorf("%s: reader Contains(1), Contains(2), want (true, false)", name)
+		}
+		rh.Leave()
+
+		rh.Close()
+	}
+}
+
+func TestAppendKeys(t *testing.T) {
+	lrm := New[int, int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	for i := 0; i < 10; i++ {
+		lrm.Set(i, i)
+	}
+
+	lrm.Commit()
+
+	buf := make([]int, 0, 16)
+
+	rh.Enter()
+	keys := rh.AppendKeys(buf[:0])
+	rh.Leave()
+
+	if len(keys) != 10 || &keys[0] != &buf[:1][0] {
+		t.Errorf("AppendKeys(): want 10 keys in the given buffer, got %d", len(keys))
+	}
+
+	sum := 0
+	for _, k := range keys {
+		sum += k
+	}
+
+	if sum != 45 {
+		t.Errorf("AppendKeys(): want key sum 45, got %d", sum)
+	}
+
+	rh.Enter()
+	allocs := testing.AllocsPerRun(100, func() { keys = rh.AppendKeys(keys[:0]) })
+	rh.Leave()
+
+	if allocs != 0 {
+		t.Errorf("AppendKeys() into a large enough buffer, want 0 allocs, got %f", allocs)
+	}
+}
+
+func TestEntered(t *testing.T) {
+	lrm := New[int, int]()
+	rh := lrm.NewReadHandler()
+
+	if rh.Entered() {
+		t.Errorf("Entered() of a fresh handler, want false")
+	}
+
+	epoch := rh.EpochCount()
+
+	rh.Enter()
+
+	if !rh.Entered() || rh.EpochCount() != epoch+1 || rh.EpochCount()%2 != 1 {
+		t.Errorf("after Enter(): Entered() = %t, EpochCount() = %d", rh.Entered(), rh.EpochCount())
+	}
+
+	rh.Leave()
+	rh.Enter()
+	rh.Leave()
+
+	if rh.Entered() || rh.EpochCount() != epoch+4 {
+		t.Errorf("after 2 entries: Entered() = %t, EpochCount() = %d", rh.Entered(), rh.EpochCount())
+	}
+
+	rh.Close()
+
+	if rh.Entered() || rh.EpochCount() != 0 {
+		t.Errorf("after Close(): Entered() = %t, EpochCount() = %d", rh.Entered(), rh.EpochCount())
+	}
+
+	var zero ReadHandler[int, int]
+	if zero.Entered() || zero.EpochCount() != 0 {
+		t.Errorf("zero handler: Entered() = %t, EpochCount() = %d", zero.Entered(), zero.EpochCount())
+	}
+}
+
+func TestHandlersDuringCommit(t *testing.T) {
+	lrm := New[int, int]()
+
+	straggler := lrm.NewReadHandler()
+	straggler.Enter()
+
+	committed := make(chan struct{})
+
+	go func() {
+		lrm.Commit()
+		close(committed)
+	}()
+
+	// wait until the commit has swapped and is waiting for the straggler
+	for lrm.readMap.Load().gen == 0 {
+		runtime.Gosched()
+	}
+
+	// neither creating nor closing handlers must block on the waiting commit
+	rh := lrm.NewReadHandler()
+	rh.Enter()
+	rh.Leave()
+	rh.Close()
+
+	select {
+	case <-committed:
+		t.Fatalf("commit did not wait for the straggler")
+	default:
+	}
+
+	straggler.Leave()
+	<-committed
+
+	straggler.Close()
+}
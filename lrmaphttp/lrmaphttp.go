@@ -0,0 +1,138 @@
+// Package lrmaphttp serves the committed state of an lrmap.LRMap over HTTP for debugging and
+// administration.
+package lrmaphttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/jwkohnen/lrmap"
+)
+
+const (
+	defaultLimit = 100
+	maxLimit     = 10000
+)
+
+type (
+	// Page is the response to an entries request.
+	Page[K comparable, V any] struct {
+		// Total is the number of entries that match the prefix.
+		Total   int           `json:"total"`
+		Offset  int           `json:"offset"`
+		Entries []Entry[K, V] `json:"entries"`
+	}
+
+	Entry[K comparable, V any] struct {
+		Key   K `json:"key"`
+		Value V `json:"value"`
+	}
+)
+
+// Handler returns a handler that serves Stats() under the path suffix "/stats" and the
+// committed entries under any other path.  Entries are ordered by the string form of their
+// keys (fmt.Sprint) and can be filtered and paged by the query parameters "prefix" (of the
+// string form of the key), "offset", and "limit" (default 100).
+//
+// Every request to the entries copies and sorts the matching keys, so the handler is meant for
+// debugging and administration, not for serving traffic.
+func Handler[K comparable, V any](m *lrmap.LRMap[K, V]) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/stats") {
+			writeJSON(w, m.Stats())
+
+			return
+		}
+
+		query := r.URL.Query()
+
+		offset, err := intParam(query.Get("offset"), 0)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		limit, err := intParam(query.Get("limit"), defaultLimit)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+
+			return
+		}
+
+		p, err := page(m, query.Get("prefix"), offset, min(limit, maxLimit))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+
+			return
+		}
+
+		writeJSON(w, p)
+	})
+}
+
+// page fails with lrmap.ErrClosed if the map has been closed.
+func page[K comparable, V any](m *lrmap.LRMap[K, V], prefix string, offset, limit int) (Page[K, V], error) {
+	type match struct {
+		name  string
+		entry Entry[K, V]
+	}
+
+	rh := m.NewReadHandler()
+	defer rh.Recycle()
+
+	if err := rh.TryEnter(); err != nil {
+		return Page[K, V]{}, err // nolint:exhaustivestruct
+	}
+
+	defer rh.Leave()
+
+	var matches []match
+
+	rh.Iterate(func(key K, value V) bool {
+		if name := fmt.Sprint(key); strings.HasPrefix(name, prefix) {
+			matches = append(matches, match{name: name, entry: Entry[K, V]{Key: key, Value: value}})
+		}
+
+		return true
+	})
+
+	slices.SortFunc(matches, func(a, b match) int { return strings.Compare(a.name, b.name) })
+
+	p := Page[K, V]{Total: len(matches), Offset: offset, Entries: []Entry[K, V]{}}
+
+	// clamp before adding, since offset and limit come from the client and may overflow
+	start := min(offset, len(matches))
+	end := start + min(limit, len(matches)-start)
+
+	for _, e := range matches[start:end] {
+		p.Entries = append(p.Entries, e.entry)
+	}
+
+	return p, nil
+}
+
+func intParam(s string, def int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(s)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid parameter %q", s) // nolint:goerr113
+	}
+
+	return n, nil
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
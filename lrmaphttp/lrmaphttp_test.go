@@ -0,0 +1,94 @@
+package lrmaphttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jwkohnen/lrmap"
+)
+
+func TestHandler(t *testing.T) {
+	m := lrmap.New[string, int]()
+	for i, key := range []string{"user/b", "user/a", "user/c", "group/a"} {
+		m.Set(key, i)
+	}
+
+	m.Commit()
+
+	srv := httptest.NewServer(Handler(m))
+	defer srv.Close()
+
+	var p Page[string, int]
+
+	get(t, srv.URL+"/?prefix=user/&offset=1&limit=1", &p)
+
+	if p.Total != 3 || p.Offset != 1 || len(p.Entries) != 1 || p.Entries[0] != (Entry[string, int]{Key: "user/b", Value: 0}) {
+		t.Errorf("page = %+v", p)
+	}
+
+	get(t, srv.URL+"/?offset=10", &p)
+
+	if p.Total != 4 || len(p.Entries) != 0 {
+		t.Errorf("page past the end = %+v", p)
+	}
+
+	get(t, srv.URL+"/?offset=9223372036854775807", &p)
+
+	if p.Total != 4 || len(p.Entries) != 0 {
+		t.Errorf("page at the largest offset = %+v", p)
+	}
+
+	var s lrmap.Stats
+
+	get(t, srv.URL+"/debug/stats", &s)
+
+	if s.Generation != 1 || s.CommittedLen != 4 {
+		t.Errorf("stats = %+v", s)
+	}
+
+	resp, err := http.Get(srv.URL + "/?limit=x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("invalid limit: status %d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err = http.Get(srv.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("closed map: status %d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+}
+
+func get(t *testing.T, url string, v any) {
+	t.Helper()
+
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET %s: status %d", url, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,45 @@
+package lrmap
+
+import (
+	"path"
+	"regexp"
+	"strings"
+)
+
+// IterateMatch calls fn for all entries of the live view whose keys match the glob pattern (see
+// path.Match), in ascending key order, until fn returns false.  Only keys that start with the
+// literal prefix of pattern are visited.  It returns path.ErrBadPattern for malformed patterns.
+func IterateMatch[K ~string, V any](rh *ReadHandler[K, V], pattern string, fn func(K, V) bool) error {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return err
+	}
+
+	prefix := pattern
+	if i := strings.IndexAny(pattern, `*?[\`); i >= 0 {
+		prefix = pattern[:i]
+	}
+
+	IteratePrefix(rh, prefix, func(key K, value V) bool {
+		// the pattern has been checked, so matching cannot fail
+		if ok, _ := path.Match(pattern, string(key)); !ok {
+			return true
+		}
+
+		return fn(key, value)
+	})
+
+	return nil
+}
+
+// IterateRegexp is like IterateMatch, but matches the keys against re.
+func IterateRegexp[K ~string, V any](rh *ReadHandler[K, V], re *regexp.Regexp, fn func(K, V) bool) {
+	prefix, _ := re.LiteralPrefix()
+
+	IteratePrefix(rh, prefix, func(key K, value V) bool {
+		if !re.MatchString(string(key)) {
+			return true
+		}
+
+		return fn(key, value)
+	})
+}
@@ -0,0 +1,55 @@
+package lrmap
+
+import (
+	"errors"
+	"path"
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestIterateMatch(t *testing.T) {
+	lrm := New[string, int]()
+
+	for i, k := range []string{"host/a.example", "host/b.example", "host/a.test", "hostx", "user/a"} {
+		lrm.Set(k, i)
+	}
+
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	collect := func() (*[]string, func(string, int) bool) {
+		var keys []string
+
+		return &keys, func(k string, _ int) bool {
+			keys = append(keys, k)
+
+			return true
+		}
+	}
+
+	keys, fn := collect()
+	if err := IterateMatch(rh, "host/*.example", fn); err != nil {
+		t.Fatalf("IterateMatch(): %v", err)
+	}
+
+	if want := []string{"host/a.example", "host/b.example"}; !slices.Equal(*keys, want) {
+		t.Errorf("IterateMatch(host/*.example): want %v, got %v", want, *keys)
+	}
+
+	if err := IterateMatch(rh, "host/[", fn); !errors.Is(err, path.ErrBadPattern) {
+		t.Errorf("IterateMatch(host/[), want ErrBadPattern, got %v", err)
+	}
+
+	keys, fn = collect()
+	IterateRegexp(rh, regexp.MustCompile(`^host/a\.`), fn)
+
+	if want := []string{"host/a.example", "host/a.test"}; !slices.Equal(*keys, want) {
+		t.Errorf("IterateRegexp(^host/a\\.): want %v, got %v", want, *keys)
+	}
+}
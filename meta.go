@@ -0,0 +1,93 @@
+package lrmap
+
+import "time"
+
+// EntryMeta tells when an entry has been written.
+type EntryMeta struct {
+	// Created is the time the key has been set after it had not existed, Updated the time it
+	// has been written last.
+	Created time.Time
+	Updated time.Time
+
+	// Generation is the generation that has published the last write.
+	Generation uint64
+
+	// Deleted is the time the entry has been deleted, if it is a tombstone (see
+	// WithTombstones), and zero otherwise.
+	Deleted time.Time
+}
+
+// WithEntryMeta makes the map keep track of when each entry has been created and updated, see
+// ReadHandler.GetMeta.  It costs a second map per arena and a clock reading per write.
+func WithEntryMeta[K comparable, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.entryMeta = true
+	}
+}
+
+// GetMeta returns the metadata of key in the live view.  It requires WithEntryMeta.  With
+// WithTombstones, it also returns the metadata of deleted keys that have not been purged.
+func (rh *ReadHandler[K, V]) GetMeta(key K) (EntryMeta, bool) {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	if !rh.inner.lrmap.entryMeta {
+		panic("illegal use: GetMeta() requires WithEntryMeta()")
+	}
+
+	meta, ok := rh.inner.live.meta[rh.inner.lrmap.normalize(key)]
+
+	return meta, ok
+}
+
+// stamp records the metadata of a write to key in the write map.  The caller must hold m.mu.
+func (m *LRMap[K, V]) stamp(key K, set bool) {
+	if !m.entryMeta {
+		return
+	}
+
+	meta := m.writeMap.Load().meta
+
+	if !set && !m.tombstones {
+		delete(meta, key)
+
+		return
+	}
+
+	now := m.clock.Now()
+	entry, ok := meta[key]
+
+	if !set {
+		// keep the time of the first deletion of a tombstone
+		if ok && entry.Deleted.IsZero() {
+			entry.Deleted = now
+			entry.Generation = m.generation + 1
+			meta[key] = entry
+		}
+
+		return
+	}
+
+	if !ok || !entry.Deleted.IsZero() {
+		entry = EntryMeta{Created: now} // nolint:exhaustivestruct
+	}
+
+	entry.Updated = now
+	entry.Generation = m.generation + 1
+	meta[key] = entry
+}
+
+// syncMeta copies the metadata of key from the read map, which holds the final state of all
+// keys of the redo log, when replaying on the write map.
+func (m *LRMap[K, V]) syncMeta(key K) {
+	write := m.writeMap.Load().meta
+
+	if entry, ok := m.readMap.Load().meta[key]; ok {
+		write[key] = entry
+	} else {
+		delete(write, key)
+	}
+}
@@ -0,0 +1,44 @@
+package lrmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryMeta(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(100, 0)} // nolint:exhaustivestruct
+	lrm := New(WithEntryMeta[string, int](), WithClock[string, int](clock))
+
+	lrm.Set("a", 1)
+	lrm.Set("b", 1)
+	lrm.Commit()
+
+	clock.now = time.Unix(200, 0)
+
+	lrm.Set("a", 2)
+	lrm.Delete("b")
+	lrm.Commit()
+
+	// publish the side the previous commit has replayed on
+	lrm.Set("c", 3)
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	want := EntryMeta{Created: time.Unix(100, 0), Updated: time.Unix(200, 0), Generation: 2}
+	if got, ok := rh.GetMeta("a"); !ok || got != want {
+		t.Errorf("GetMeta(a): want %+v, got %+v, %t", want, got, ok)
+	}
+
+	if _, ok := rh.GetMeta("b"); ok {
+		t.Errorf("GetMeta(b): want no metadata for a deleted key")
+	}
+
+	if got, _ := rh.GetMeta("c"); got.Generation != 3 {
+		t.Errorf("GetMeta(c): want generation 3, got %d", got.Generation)
+	}
+}
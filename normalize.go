@@ -0,0 +1,37 @@
+package lrmap
+
+// WithKeyNormalizer makes the map pass every key through fn before it reads or writes it, so
+// that logically equal keys (e.g. case-insensitive host names, or IDs with surrounding white
+// space) end up as the same entry.  It applies to the writer methods, to read handlers, and to
+// operations fed in by ApplyOps (and thus Replica and Restore).  fn must be idempotent, i.e.
+// fn(fn(k)) == fn(k).
+//
+// Iteration and GetMany yield the normalized keys, and prefixes and patterns (see IteratePrefix) are
+// matched against them as given.
+func WithKeyNormalizer[K comparable, V any](fn func(K) K) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.normalizer = fn
+	}
+}
+
+func (m *LRMap[K, V]) normalize(key K) K {
+	if m.normalizer == nil {
+		return key
+	}
+
+	return m.normalizer(key)
+}
+
+// normalizeAll returns the normalized keys, copying keys only if there is a normalizer.
+func (m *LRMap[K, V]) normalizeAll(keys []K) []K {
+	if m.normalizer == nil {
+		return keys
+	}
+
+	normalized := make([]K, len(keys))
+	for i, key := range keys {
+		normalized[i] = m.normalizer(key)
+	}
+
+	return normalized
+}
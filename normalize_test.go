@@ -0,0 +1,54 @@
+package lrmap
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKeyNormalizer(t *testing.T) {
+	lrm := New(WithKeyNormalizer[string, int](func(key string) string {
+		return strings.ToLower(strings.TrimSpace(key))
+	}))
+
+	lrm.Set("Example.COM", 1)
+	lrm.Set(" example.com ", 2)
+	lrm.Set("other.org", 3)
+	lrm.Delete("OTHER.org")
+
+	if got, ok := lrm.GetOK("EXAMPLE.com"); !ok || got != 2 {
+		t.Errorf("GetOK(EXAMPLE.com): want 2, true, got %d, %t", got, ok)
+	}
+
+	lrm.Commit()
+
+	err := lrm.ApplyOps([]Op[string, int]{{Kind: OpSet, Key: "Replicated.NET", Value: 4}})
+	if err != nil {
+		t.Fatalf("ApplyOps(): %v", err)
+	}
+
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if got := rh.Len(); got != 2 {
+		t.Errorf("Len(): want 2, got %d", got)
+	}
+
+	for key, want := range map[string]int{"example.com": 2, "eXample.com": 2, "replicated.net ": 4} {
+		if got := rh.Get(key); got != want {
+			t.Errorf("Get(%q): want %d, got %d", key, want, got)
+		}
+	}
+
+	if rh.Contains("Other.org") {
+		t.Errorf("Contains(Other.org): want false after Delete(OTHER.org)")
+	}
+
+	if got := rh.GetMany("EXAMPLE.COM"); got["example.com"] != 2 {
+		t.Errorf("GetMany(EXAMPLE.COM): want map[example.com:2], got %v", got)
+	}
+}
@@ -0,0 +1,126 @@
+package lrmap
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrUnsupportedOp is returned by ApplyOps for operations the map cannot apply.
+var ErrUnsupportedOp = errors.New("unsupported operation")
+
+type OpKind int8
+
+const (
+	OpSet OpKind = iota
+	OpDelete
+	OpAdd
+	OpDeletePrefix
+	OpPurge
+)
+
+func (k OpKind) String() string {
+	switch k {
+	case OpSet:
+		return "set"
+	case OpDelete:
+		return "delete"
+	case OpAdd:
+		return "add"
+	case OpDeletePrefix:
+		return "delete prefix"
+	case OpPurge:
+		return "purge"
+	default:
+		return "unknown"
+	}
+}
+
+// Op is a write operation that has been applied to the write map but not yet published.  Value
+// is the zero value for deletions and the delta for additions.  For OpDeletePrefix, Key is the
+// prefix (see DeletePrefix).  OpPurge removes the tombstone of Key (see Purge).
+type Op[K comparable, V any] struct {
+	Kind  OpKind
+	Key   K
+	Value V
+}
+
+// pendingOps copies the redo log.  The caller must hold m.mu.
+func (m *LRMap[K, V]) pendingOps() []Op[K, V] {
+	ops := make([]Op[K, V], len(m.redoLog))
+
+	for i, op := range m.redoLog {
+		ops[i] = Op[K, V]{Kind: op.typ, Key: op.key, Value: op.value}
+	}
+
+	return ops
+}
+
+// ApplyOps applies ops to the write map as if they had been written by Set, Delete, and Add,
+// e.g. to feed a replication stream or a write-ahead log into the map.  Unlike Set, it does
+// not call a reducer, since the values of the operations are final.  If the validator rejects
+// any entry, or the map cannot apply an operation, ApplyOps applies none of them.
+//
+// Additions require that the map either has been created WithAdd or has seen a call to Add,
+// and prefix deletions likewise require WithDeletePrefix or a call to DeletePrefix.
+func (m *LRMap[K, V]) ApplyOps(ops []Op[K, V]) error {
+	m.lock()
+	defer m.mu.Unlock()
+
+	if err := m.writable(); err != nil {
+		return err
+	}
+
+	for _, op := range ops {
+		switch op.Kind {
+		case OpSet:
+			if err := m.validate(m.normalize(op.Key), op.Value); err != nil {
+				return err
+			}
+		case OpDelete:
+		case OpAdd:
+			if m.add == nil {
+				return fmt.Errorf("%w: %v on a map without WithAdd", ErrUnsupportedOp, op.Kind)
+			}
+		case OpDeletePrefix:
+			if m.hasPrefix == nil {
+				return fmt.Errorf("%w: %v on a map without WithDeletePrefix", ErrUnsupportedOp, op.Kind)
+			}
+		case OpPurge:
+			if !m.tombstones {
+				return fmt.Errorf("%w: %v on a map without WithTombstones", ErrUnsupportedOp, op.Kind)
+			}
+		default:
+			return fmt.Errorf("%w: %v", ErrUnsupportedOp, op.Kind)
+		}
+	}
+
+	for _, op := range ops {
+		if op.Kind != OpDeletePrefix {
+			op.Key = m.normalize(op.Key)
+		}
+
+		switch op.Kind {
+		case OpSet:
+			m.set(op.Key, op.Value)
+		case OpDelete:
+			m.delete(op.Key)
+		case OpAdd:
+			m.syncKey(op.Key)
+
+			data := m.writeMap.Load().data
+			old, _ := data.Get(op.Key)
+			sum := m.add(old, op.Value)
+			op.Key = m.intern(op.Key)
+			m.written(data, op.Key, sum, true)
+			data.Set(op.Key, sum)
+
+			m.log(operation[K, V]{typ: OpAdd, key: op.Key, value: op.Value})
+		case OpDeletePrefix:
+			m.deletePrefix(op.Key)
+		case OpPurge:
+			m.purge(op.Key)
+		}
+	}
+
+	return nil
+}
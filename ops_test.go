@@ -0,0 +1,48 @@
+package lrmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestApplyOps(t *testing.T) {
+	var seen []Op[string, int]
+
+	src := New(WithPreCommit(func(ops []Op[string, int]) error {
+		seen = ops
+
+		return nil
+	}))
+
+	src.Set("a", 1)
+	src.Set("b", 2)
+	src.Delete("a")
+	Add(src, "b", 3)
+	src.Commit()
+
+	dst := New(WithAdd[string, int]())
+	dst.Set("a", 0)
+
+	if err := dst.ApplyOps(seen); err != nil {
+		t.Fatalf("ApplyOps(): %v", err)
+	}
+
+	dst.Commit()
+
+	if want := map[string]int{"b": 5}; !dst.EqualCommitted(want, func(a, b int) bool { return a == b }) {
+		t.Errorf("replayed map is %v, want %v", dst, want)
+	}
+}
+
+func TestApplyOpsUnsupported(t *testing.T) {
+	lrm := New[string, int]()
+
+	err := lrm.ApplyOps([]Op[string, int]{{Kind: OpSet, Key: "a", Value: 1}, {Kind: OpAdd, Key: "b", Value: 1}})
+	if !errors.Is(err, ErrUnsupportedOp) {
+		t.Errorf("ApplyOps(add), want ErrUnsupportedOp, got %v", err)
+	}
+
+	if lrm.Contains("a") {
+		t.Errorf("ApplyOps() has partially applied a rejected batch")
+	}
+}
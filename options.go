@@ -0,0 +1,19 @@
+package lrmap
+
+type Option[K comparable, V any] func(*LRMap[K, V])
+
+// WithArena replaces the default MapArena.  newArena is called once for each side of the map
+// and must return empty, distinct arenas.
+func WithArena[K comparable, V any](newArena func() Arena[K, V]) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.newArena = newArena
+	}
+}
+
+// WithOrderedArena makes both arenas keep their keys ordered by compare, which enables
+// ReadHandler.Range, ReadHandler.RangeDesc and ReadHandler.IterateDesc.
+func WithOrderedArena[K comparable, V any](compare func(K, K) int) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.newArena = func() Arena[K, V] { return newBTree[K, V](compare) }
+	}
+}
@@ -0,0 +1,285 @@
+package lrmap
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+	"slices"
+	"sync"
+)
+
+type (
+	// PartitionedMap splits one logical map into independent partitions, e.g. by key range,
+	// each backed by its own LRMap.  Commits of different partitions run in parallel, and a
+	// commit only waits for the readers that have read from its partition and only replays
+	// that partition's redo log.  This bounds both for very large maps.
+	//
+	// Writes are still serialized per partition only; there is no ordering of writes across
+	// partitions, and a commit of all partitions publishes them one partition after the other.
+	PartitionedMap[K comparable, V any] struct {
+		parts     []*LRMap[K, V]
+		partition func(K) int
+	}
+
+	// PartitionedReadHandler reads a PartitionedMap.  It enters a partition the first time it
+	// reads from it after Enter, so each partition is seen at a consistent point in time, but
+	// different partitions may be seen at different points in time.
+	PartitionedReadHandler[K comparable, V any] struct {
+		parts   []*ReadHandler[K, V]
+		m       *PartitionedMap[K, V]
+		entered bool
+	}
+)
+
+// NewPartitioned returns a map of n partitions.  partition maps each key to its partition in
+// [0, n), and must always map a key to the same partition.  Partition i is created with the
+// options returned by opts(i); a nil opts creates all partitions with the defaults.
+//
+// Options that only configure the map, e.g. WithRedoCompaction, WithTombstones, WithAdd or a
+// stateless WithPreCommit hook, may be shared by all partitions.  Options that carry an
+// instance, e.g. WithPersister, WithClock with a manual clock, a WithMemoryBudget evict
+// function or hooks with state of their own, must be created per partition, since the
+// partitions would use the same instance concurrently otherwise.
+func NewPartitioned[K comparable, V any](
+	n int,
+	partition func(K) int,
+	opts func(partition int) []Option[K, V],
+) *PartitionedMap[K, V] {
+	if n < 1 {
+		panic(fmt.Errorf("illegal use: number of partitions must be positive, got %d", n)) // nolint:goerr113
+	}
+
+	m := &PartitionedMap[K, V]{parts: make([]*LRMap[K, V], n), partition: partition}
+
+	for i := range m.parts {
+		if opts == nil {
+			m.parts[i] = New[K, V]()
+		} else {
+			m.parts[i] = New(opts(i)...)
+		}
+	}
+
+	return m
+}
+
+// RangePartition returns a partition function for NewPartitioned that splits keys at bounds,
+// which must be sorted: keys less than bounds[0] map to partition 0, keys in [bounds[i-1],
+// bounds[i]) to partition i, and all other keys to partition len(bounds).
+func RangePartition[K cmp.Ordered](bounds ...K) func(K) int {
+	if !slices.IsSorted(bounds) {
+		panic("illegal use: partition bounds must be sorted")
+	}
+
+	return func(key K) int {
+		i, found := slices.BinarySearch(bounds, key)
+		if found {
+			i++
+		}
+
+		return i
+	}
+}
+
+// Partitions returns the number of partitions.
+func (m *PartitionedMap[K, V]) Partitions() int { return len(m.parts) }
+
+// Partition returns the map backing partition i, e.g. to inspect its Stats.
+func (m *PartitionedMap[K, V]) Partition(i int) *LRMap[K, V] { return m.parts[i] }
+
+func (m *PartitionedMap[K, V]) Set(key K, value V) { m.part(key).Set(key, value) }
+func (m *PartitionedMap[K, V]) Delete(key K)       { m.part(key).Delete(key) }
+
+func (m *PartitionedMap[K, V]) Get(key K) V {
+	value, _ := m.GetOK(key)
+
+	return value
+}
+
+func (m *PartitionedMap[K, V]) GetOK(key K) (V, bool) { return m.part(key).GetOK(key) }
+
+// Commit commits all partitions in parallel.
+func (m *PartitionedMap[K, V]) Commit() { _ = m.TryCommit() }
+
+// TryCommit is like Commit, but returns the errors of partitions whose commit has been vetoed.
+// The other partitions are committed nonetheless.
+func (m *PartitionedMap[K, V]) TryCommit() error {
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, len(m.parts))
+	)
+
+	for i, part := range m.parts {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := part.CommitContext(context.Background()); err != nil {
+				errs[i] = fmt.Errorf("partition %d: %w", i, err)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// CommitPartition commits partition i only.
+func (m *PartitionedMap[K, V]) CommitPartition(i int) error { return m.parts[i].TryCommit() }
+
+// Close closes all partitions.
+func (m *PartitionedMap[K, V]) Close() error {
+	errs := make([]error, len(m.parts))
+
+	for i, part := range m.parts {
+		errs[i] = part.Close()
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *PartitionedMap[K, V]) part(key K) *LRMap[K, V] {
+	i := m.partition(key)
+	if i < 0 || i >= len(m.parts) {
+		panic(fmt.Errorf("illegal use: key %v mapped to partition %d of %d", key, i, len(m.parts))) // nolint:goerr113
+	}
+
+	return m.parts[i]
+}
+
+func (m *PartitionedMap[K, V]) NewReadHandler() *PartitionedReadHandler[K, V] {
+	rh := &PartitionedReadHandler[K, V]{
+		parts:   make([]*ReadHandler[K, V], len(m.parts)),
+		m:       m,
+		entered: false,
+	}
+
+	for i, part := range m.parts {
+		rh.parts[i] = part.NewReadHandler()
+	}
+
+	return rh
+}
+
+func (rh *PartitionedReadHandler[K, V]) Enter() {
+	if rh.entered {
+		panic("reader illegal state: must not Enter() twice")
+	}
+
+	rh.entered = true
+}
+
+func (rh *PartitionedReadHandler[K, V]) Leave() {
+	if !rh.entered {
+		panic("reader illegal state: must not Leave() twice")
+	}
+
+	rh.entered = false
+
+	for _, part := range rh.parts {
+		if part.Entered() {
+			part.Leave()
+		}
+	}
+}
+
+func (rh *PartitionedReadHandler[K, V]) Close() {
+	for _, part := range rh.parts {
+		part.Close()
+	}
+}
+
+func (rh *PartitionedReadHandler[K, V]) Get(key K) V {
+	value, _ := rh.GetOK(key)
+
+	return value
+}
+
+func (rh *PartitionedReadHandler[K, V]) GetOK(key K) (V, bool) {
+	return rh.enter(rh.m.partition(key)).GetOK(key)
+}
+
+func (rh *PartitionedReadHandler[K, V]) Contains(key K) bool {
+	return rh.enter(rh.m.partition(key)).Contains(key)
+}
+
+// Len enters all partitions and sums their lengths.
+func (rh *PartitionedReadHandler[K, V]) Len() int {
+	n := 0
+
+	for i := range rh.parts {
+		n += rh.enter(i).Len()
+	}
+
+	return n
+}
+
+// Iterate calls fn for all entries until fn returns false, partition by partition in order.
+// It enters all partitions it iterates.
+func (rh *PartitionedReadHandler[K, V]) Iterate(fn func(_ K, _ V) bool) {
+	for i := range rh.parts {
+		ok := true
+
+		rh.enter(i).Iterate(func(k K, v V) bool {
+			ok = fn(k, v)
+
+			return ok
+		})
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// enter returns the handler of partition i, entering it on first use.
+func (rh *PartitionedReadHandler[K, V]) enter(i int) *ReadHandler[K, V] {
+	if !rh.entered {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	if i < 0 || i >= len(rh.parts) {
+		panic(fmt.Errorf("illegal use: key mapped to partition %d of %d", i, len(rh.parts))) // nolint:goerr113
+	}
+
+	part := rh.parts[i]
+	if !part.Entered() {
+		part.Enter()
+	}
+
+	return part
+}
+
+// IteratePartitionedSorted is like PartitionedReadHandler.Iterate, but in a stable order:
+// partition by partition in order, and within each partition in ascending key order (see
+// IterateSorted).  With RangePartition, this is the ascending key order of the whole map.
+//
+// Each partition is entered on first use and stays entered until Leave, so each partition is
+// iterated at a single point in time, which is the same for all iterations between Enter and
+// Leave.  Exports and checksums of the same committed states are therefore reproducible.
+func IteratePartitionedSorted[K cmp.Ordered, V any](rh *PartitionedReadHandler[K, V], fn func(K, V) bool) {
+	for i := range rh.parts {
+		ok := true
+
+		IterateSorted(rh.enter(i), func(k K, v V) bool {
+			ok = fn(k, v)
+
+			return ok
+		})
+
+		if !ok {
+			return
+		}
+	}
+}
+
+// AllPartitionedSorted returns an iterator over rh in the order of IteratePartitionedSorted.
+// The handler must be entered while the iterator is in use.
+func AllPartitionedSorted[K cmp.Ordered, V any](rh *PartitionedReadHandler[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		IteratePartitionedSorted(rh, yield)
+	}
+}
@@ -0,0 +1,158 @@
+package lrmap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestRangePartition(t *testing.T) {
+	partition := RangePartition(10, 20)
+
+	for key, want := range map[int]int{-5: 0, 9: 0, 10: 1, 19: 1, 20: 2, 100: 2} {
+		if got := partition(key); got != want {
+			t.Errorf("partition(%d), want %d, got %d", key, want, got)
+		}
+	}
+}
+
+func TestPartitionedMap(t *testing.T) {
+	m := NewPartitioned[int, int](3, RangePartition(10, 20), nil)
+
+	for i := 0; i < 30; i++ {
+		m.Set(i, i)
+	}
+
+	m.Commit()
+
+	rh := m.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+
+	if v := rh.Get(15); v != 15 {
+		t.Errorf("Get(15), want 15, got %d", v)
+	}
+
+	// only the partition that has been read is entered, so the others commit without waiting
+	if entered := []bool{rh.parts[0].Entered(), rh.parts[1].Entered(), rh.parts[2].Entered()}; entered[0] ||
+		!entered[1] || entered[2] {
+		t.Errorf("entered partitions, want [false true false], got %v", entered)
+	}
+
+	m.Delete(25)
+
+	if err := m.CommitPartition(2); err != nil {
+		t.Fatal(err)
+	}
+
+	if rh.Contains(25) {
+		t.Error("Contains(25) after committed Delete")
+	}
+
+	var keys []int
+
+	rh.Iterate(func(k, _ int) bool {
+		keys = append(keys, k)
+
+		return k < 12
+	})
+
+	// partitions are iterated in order
+	if len(keys) == 0 || keys[len(keys)-1] < 10 || keys[len(keys)-1] >= 20 {
+		t.Errorf("iteration did not stop in partition 1: %v", keys)
+	}
+
+	if n := rh.Len(); n != 29 {
+		t.Errorf("Len(), want 29, got %d", n)
+	}
+
+	rh.Leave()
+
+	if err := m.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIteratePartitionedSorted(t *testing.T) {
+	m := NewPartitioned[int, int](3, func(k int) int { return k % 3 }, nil)
+
+	for i := 9; i >= 0; i-- {
+		m.Set(i, i)
+	}
+
+	m.Commit()
+
+	rh := m.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+
+	want := []int{0, 3, 6, 9, 1, 4, 7, 2, 5, 8}
+
+	var keys []int
+	for k := range AllPartitionedSorted(rh) {
+		keys = append(keys, k)
+	}
+
+	if !slices.Equal(keys, want) {
+		t.Errorf("keys, want %v, got %v", want, keys)
+	}
+
+	// pending writes are not seen
+	m.Set(10, 10)
+
+	keys = keys[:0]
+	IteratePartitionedSorted(rh, func(k, _ int) bool {
+		keys = append(keys, k)
+
+		return k != 4
+	})
+
+	if want := want[:6]; !slices.Equal(keys, want) {
+		t.Errorf("keys until 4, want %v, got %v", want, keys)
+	}
+
+	rh.Leave()
+}
+
+func TestPartitionedMapVeto(t *testing.T) {
+	errVeto := errors.New("veto")
+
+	veto := WithPreCommit(func(pending []Op[int, int]) error {
+		if len(pending) > 0 && pending[0].Key == 1 {
+			return errVeto
+		}
+
+		return nil
+	})
+
+	var created []int
+
+	m := NewPartitioned(2, func(k int) int { return k % 2 }, func(partition int) []Option[int, int] {
+		created = append(created, partition)
+
+		return []Option[int, int]{veto}
+	})
+
+	if want := []int{0, 1}; !slices.Equal(created, want) {
+		t.Fatalf("options created for partitions %v, want %v", created, want)
+	}
+
+	m.Set(0, 0)
+	m.Set(1, 1)
+
+	if err := m.TryCommit(); !errors.Is(err, errVeto) {
+		t.Errorf("TryCommit(), want veto, got %v", err)
+	}
+
+	rh := m.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if !rh.Contains(0) || rh.Contains(1) {
+		t.Error("vetoed partition has been published, or the other one has not")
+	}
+}
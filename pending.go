@@ -0,0 +1,65 @@
+package lrmap
+
+import "context"
+
+// WithPendingLimit makes Set, TrySet, and Delete block while the redo log holds n or more
+// operations, until a commit has worked it off, so that producers are throttled when commits
+// cannot keep up.  The limit is a high-water mark: concurrent writers may overshoot it by one
+// operation each.  Only use it if other goroutines commit, or writes block forever.
+func WithPendingLimit[K comparable, V any](n int) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.pendingLimit = n
+	}
+}
+
+// WaitPendingBelow waits until the redo log holds fewer than n operations, i.e. until commits
+// have caught up with the writes, or until ctx is done.  It returns ErrFrozen or ErrClosed if
+// the map has been frozen or closed in the meantime.
+func (m *LRMap[K, V]) WaitPendingBelow(ctx context.Context, n int) error {
+	for {
+		m.lock()
+
+		if err := m.writable(); err != nil {
+			m.mu.Unlock()
+
+			return err
+		}
+
+		if len(m.redoLog) < n {
+			m.mu.Unlock()
+
+			return nil
+		}
+
+		if m.drained == nil {
+			m.drained = make(chan struct{})
+		}
+
+		drained := m.drained
+
+		m.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-drained:
+		}
+	}
+}
+
+// throttle blocks while the redo log is at its limit (see WithPendingLimit).  The caller must
+// not hold m.mu.
+func (m *LRMap[K, V]) throttle() {
+	if m.pendingLimit > 0 {
+		_ = m.WaitPendingBelow(context.Background(), m.pendingLimit)
+	}
+}
+
+// notifyDrained wakes up the writers waiting for the redo log to shrink.  The caller must hold
+// m.mu.
+func (m *LRMap[K, V]) notifyDrained() {
+	if m.drained != nil {
+		close(m.drained)
+		m.drained = nil
+	}
+}
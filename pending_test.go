@@ -0,0 +1,75 @@
+package lrmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitPendingBelow(t *testing.T) {
+	lrm := New[int, int]()
+
+	for i := 0; i < 10; i++ {
+		lrm.Set(i, i)
+	}
+
+	if err := lrm.WaitPendingBelow(context.Background(), 11); err != nil {
+		t.Errorf("WaitPendingBelow(11) with 10 pending ops: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := lrm.WaitPendingBelow(ctx, 10); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WaitPendingBelow(10) without commit, want DeadlineExceeded, got %v", err)
+	}
+
+	done := make(chan error)
+
+	go func() { done <- lrm.WaitPendingBelow(context.Background(), 5) }()
+
+	lrm.Commit()
+
+	if err := <-done; err != nil {
+		t.Errorf("WaitPendingBelow(5) after commit: %v", err)
+	}
+
+	lrm.Set(0, 0)
+
+	go func() { done <- lrm.WaitPendingBelow(context.Background(), 1) }()
+
+	_ = lrm.Close()
+
+	if err := <-done; !errors.Is(err, ErrClosed) {
+		t.Errorf("WaitPendingBelow(1) after Close(), want ErrClosed, got %v", err)
+	}
+}
+
+func TestPendingLimit(t *testing.T) {
+	lrm := New(WithPendingLimit[int, int](3))
+
+	for i := 0; i < 3; i++ {
+		lrm.Set(i, i)
+	}
+
+	set := make(chan struct{})
+
+	go func() {
+		lrm.Set(3, 3)
+		close(set)
+	}()
+
+	select {
+	case <-set:
+		t.Fatal("Set() at the pending limit has not blocked")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	lrm.Commit()
+	<-set
+
+	if s := lrm.Stats(); s.PendingOps != 1 || s.Len != 4 {
+		t.Errorf("Stats() after the blocked Set(), got %+v", s)
+	}
+}
@@ -0,0 +1,91 @@
+package lrmap
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// ErrNoPersister is returned by Checkpoint and Restore on maps without a persister.
+var ErrNoPersister = errors.New("map has no persister")
+
+// Persister stores the state of a map durably, as a checkpoint of all entries and a log of
+// the commit batches that followed it.  See the persist package for a file based
+// implementation.
+type Persister[K comparable, V any] interface {
+	// AppendOps durably records the batch of a commit.  It is called before the commit
+	// publishes the batch, and if it fails, the commit fails.
+	AppendOps(batch CommitBatch[K, V]) error
+
+	// WriteCheckpoint durably stores all entries of generation gen.  Afterwards, the batches
+	// up to gen are no longer needed.
+	WriteCheckpoint(gen uint64, entries iter.Seq2[K, V]) error
+
+	// Load calls fn with the latest checkpoint (as batches of sets with the generation of the
+	// checkpoint), followed by the recorded batches after it, in order.
+	Load(fn func(batch CommitBatch[K, V]) error) error
+}
+
+// WithPersister makes every commit record its operations with p before it publishes them.
+func WithPersister[K comparable, V any](p Persister[K, V]) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.persister = p
+	}
+}
+
+// Checkpoint stores the committed entries with the persister.  It does not hold the writer lock
+// while doing so, but keeps a read handler entered, which holds off the next commit but one.
+func (m *LRMap[K, V]) Checkpoint() error {
+	if m.persister == nil {
+		return ErrNoPersister
+	}
+
+	rh := m.NewReadHandler()
+	defer rh.Recycle()
+
+	if err := rh.TryEnter(); err != nil {
+		return err
+	}
+
+	defer rh.Leave()
+
+	return m.persister.WriteCheckpoint(rh.inner.live.gen, func(yield func(K, V) bool) {
+		rh.Iterate(yield)
+	})
+}
+
+// Restore loads the persisted state into the map and commits it with the generation it has
+// been persisted with.  It must be called before the map is used otherwise.
+func (m *LRMap[K, V]) Restore() error {
+	if m.persister == nil {
+		return ErrNoPersister
+	}
+
+	var gen uint64
+
+	err := m.persister.Load(func(batch CommitBatch[K, V]) error {
+		gen = batch.Generation
+
+		return m.ApplyOps(batch.Ops)
+	})
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+
+	if gen == 0 {
+		return nil
+	}
+
+	m.lock()
+	m.generation = gen - 1
+	m.restoring = true
+	m.mu.Unlock()
+
+	defer func() {
+		m.lock()
+		m.restoring = false
+		m.mu.Unlock()
+	}()
+
+	return m.TryCommit()
+}
@@ -0,0 +1,313 @@
+// Package persist implements lrmap.Persister backends.
+package persist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/jwkohnen/lrmap"
+)
+
+const (
+	checkpointFile = "checkpoint"
+	walFile        = "wal"
+
+	// checkpointChunk is the number of entries per checkpoint record.
+	checkpointChunk = 1024
+
+	// recordHeader is the length and the CRC-32 of a record, both as little endian uint32.
+	recordHeader = 8
+
+	// maxRecord bounds the length of a record, so that a corrupt length does not exhaust the
+	// memory.
+	maxRecord = 1 << 30
+)
+
+var errCorrupt = errors.New("corrupt record")
+
+// File is a Persister that keeps a checkpoint file and a write-ahead log in a directory.  Both
+// are sequences of gob encoded records with a length and a checksum, so that a record torn by
+// a crash is detected and dropped when loading.  Keys and values must be encodable by gob.
+type File[K comparable, V any] struct {
+	dir string
+
+	mu  sync.Mutex
+	wal *os.File
+}
+
+var _ lrmap.Persister[string, int] = (*File[string, int])(nil)
+
+// OpenFile opens (or creates) the persisted state in dir.
+func OpenFile[K comparable, V any](dir string) (*File[K, V], error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	wal, err := os.OpenFile(filepath.Join(dir, walFile), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File[K, V]{dir: dir, wal: wal}, nil
+}
+
+func (f *File[K, V]) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.wal.Close()
+}
+
+func (f *File[K, V]) AppendOps(batch lrmap.CommitBatch[K, V]) error {
+	rec, err := encodeRecord(batch)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.wal.Write(rec); err != nil {
+		return err
+	}
+
+	return f.wal.Sync()
+}
+
+// WriteCheckpoint replaces the checkpoint atomically and then drops the batches up to gen from
+// the write-ahead log.
+func (f *File[K, V]) WriteCheckpoint(gen uint64, entries iter.Seq2[K, V]) error {
+	err := writeFile(filepath.Join(f.dir, checkpointFile), func(w io.Writer) error {
+		batch := lrmap.CommitBatch[K, V]{Generation: gen, Ops: make([]lrmap.Op[K, V], 0, checkpointChunk)}
+
+		for key, value := range entries {
+			batch.Ops = append(batch.Ops, lrmap.Op[K, V]{Kind: lrmap.OpSet, Key: key, Value: value})
+
+			if len(batch.Ops) == checkpointChunk {
+				if err := writeRecord(w, batch); err != nil {
+					return err
+				}
+
+				batch.Ops = batch.Ops[:0]
+			}
+		}
+
+		// always write a record, so that an empty map has a checkpoint, too
+		return writeRecord(w, batch)
+	})
+	if err != nil {
+		return fmt.Errorf("write checkpoint: %w", err)
+	}
+
+	return f.truncate(gen)
+}
+
+// Load restores the checkpoint and the batches of the write-ahead log after it.  A torn record
+// at the end of the write-ahead log is cut off.
+func (f *File[K, V]) Load(fn func(batch lrmap.CommitBatch[K, V]) error) error {
+	var gen uint64
+
+	cp, err := os.Open(filepath.Join(f.dir, checkpointFile))
+
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return err
+	default:
+		defer cp.Close()
+
+		_, err := readRecords(bufio.NewReader(cp), func(batch lrmap.CommitBatch[K, V]) error {
+			gen = batch.Generation
+
+			return fn(batch)
+		})
+		if err != nil {
+			return fmt.Errorf("read checkpoint: %w", err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	valid, err := readRecords(bufio.NewReader(f.wal), func(batch lrmap.CommitBatch[K, V]) error {
+		if batch.Generation <= gen {
+			return nil
+		}
+
+		return fn(batch)
+	})
+	if errors.Is(err, errCorrupt) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return f.wal.Truncate(valid)
+	}
+
+	return err
+}
+
+// truncate drops the batches up to gen from the write-ahead log.  The caller must not hold f.mu.
+func (f *File[K, V]) truncate(gen uint64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if _, err := f.wal.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	path := filepath.Join(f.dir, walFile)
+
+	err := writeFile(path, func(w io.Writer) error {
+		_, err := readRecords(bufio.NewReader(f.wal), func(batch lrmap.CommitBatch[K, V]) error {
+			if batch.Generation <= gen {
+				return nil
+			}
+
+			return writeRecord(w, batch)
+		})
+		if errors.Is(err, errCorrupt) || errors.Is(err, io.ErrUnexpectedEOF) {
+			err = nil
+		}
+
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("truncate write-ahead log: %w", err)
+	}
+
+	wal, err := os.OpenFile(path, os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	_ = f.wal.Close()
+	f.wal = wal
+
+	return nil
+}
+
+// writeFile atomically and durably replaces path with what write writes.  It syncs the
+// directory after the rename, so that a crash cannot lose the new file while keeping changes
+// that the caller makes after writeFile returns, e.g. the truncated write-ahead log after a
+// checkpoint.
+func writeFile(path string, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w := bufio.NewWriter(tmp)
+
+	if err := write(w); err != nil {
+		return err
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+func syncDir(path string) error {
+	dir, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+
+	defer dir.Close()
+
+	return dir.Sync()
+}
+
+func encodeRecord[K comparable, V any](batch lrmap.CommitBatch[K, V]) ([]byte, error) {
+	var buf bytes.Buffer
+
+	buf.Write(make([]byte, recordHeader))
+
+	if err := gob.NewEncoder(&buf).Encode(batch); err != nil {
+		return nil, err
+	}
+
+	rec := buf.Bytes()
+	binary.LittleEndian.PutUint32(rec[0:], uint32(len(rec)-recordHeader))
+	binary.LittleEndian.PutUint32(rec[4:], crc32.ChecksumIEEE(rec[recordHeader:]))
+
+	return rec, nil
+}
+
+func writeRecord[K comparable, V any](w io.Writer, batch lrmap.CommitBatch[K, V]) error {
+	rec, err := encodeRecord(batch)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(rec)
+
+	return err
+}
+
+// readRecords calls fn for all records of r and returns the offset after the last valid one.
+func readRecords[K comparable, V any](r io.Reader, fn func(lrmap.CommitBatch[K, V]) error) (int64, error) {
+	var (
+		offset int64
+		header [recordHeader]byte
+	)
+
+	for {
+		if _, err := io.ReadFull(r, header[:]); err != nil {
+			if errors.Is(err, io.EOF) {
+				return offset, nil
+			}
+
+			return offset, err
+		}
+
+		length := binary.LittleEndian.Uint32(header[0:])
+		if length > maxRecord {
+			return offset, errCorrupt
+		}
+
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return offset, io.ErrUnexpectedEOF
+		}
+
+		if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(header[4:]) {
+			return offset, errCorrupt
+		}
+
+		var batch lrmap.CommitBatch[K, V]
+		if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&batch); err != nil {
+			return offset, fmt.Errorf("%w: %w", errCorrupt, err)
+		}
+
+		if err := fn(batch); err != nil {
+			return offset, err
+		}
+
+		offset += int64(recordHeader + len(payload))
+	}
+}
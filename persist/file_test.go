@@ -0,0 +1,107 @@
+package persist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jwkohnen/lrmap"
+)
+
+func TestFile(t *testing.T) {
+	dir := t.TempDir()
+
+	p, err := OpenFile[string, int](dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := lrmap.New(lrmap.WithPersister[string, int](p))
+
+	for i := 0; i < 3000; i++ {
+		m.Set(string(rune('a'+i%26))+string(rune('a'+i/26%26)), i)
+
+		if i%1000 == 999 {
+			m.Commit()
+		}
+	}
+
+	if err := m.Checkpoint(); err != nil {
+		t.Fatalf("Checkpoint(): %v", err)
+	}
+
+	m.Set("after", 1)
+	m.Delete("aa")
+	m.Commit()
+
+	m.Set("uncommitted", 1)
+
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// simulate a crash in the middle of appending a record
+	wal, err := os.OpenFile(filepath.Join(dir, walFile), os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := wal.Write([]byte{42, 0, 0, 0, 1, 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	wal.Close()
+
+	p, err = OpenFile[string, int](dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	restored := lrmap.New(lrmap.WithPersister[string, int](p))
+	if err := restored.Restore(); err != nil {
+		t.Fatalf("Restore(): %v", err)
+	}
+
+	want := make(map[string]int)
+
+	rh := m.NewReadHandler()
+	rh.Enter()
+	rh.Iterate(func(key string, value int) bool {
+		want[key] = value
+
+		return true
+	})
+	rh.Leave()
+	rh.Close()
+
+	if !restored.EqualCommitted(want, func(a, b int) bool { return a == b }) {
+		t.Errorf("restored map differs from the committed state")
+	}
+
+	if s := restored.Stats(); s.Generation != 4 {
+		t.Errorf("restored generation %d, want 4", s.Generation)
+	}
+
+	// the torn record has been cut off, so appending works again
+	restored.Set("again", 1)
+
+	if err := restored.TryCommit(); err != nil {
+		t.Fatalf("TryCommit() after restore: %v", err)
+	}
+
+	var gens []uint64
+
+	err = p.Load(func(batch lrmap.CommitBatch[string, int]) error {
+		gens = append(gens, batch.Generation)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("Load(): %v", err)
+	}
+
+	if last := gens[len(gens)-1]; last != 5 || gens[0] != 3 {
+		t.Errorf("loaded generations %v, want 3 ... 5", gens)
+	}
+}
@@ -0,0 +1,62 @@
+package persist
+
+import (
+	"sync/atomic"
+
+	"github.com/jwkohnen/lrmap"
+)
+
+// checkpointEvery is the number of commits after which Open rolls a new checkpoint.
+const checkpointEvery = 1024
+
+// Open returns a map that is persisted in the directory path (see File).  It restores the
+// latest checkpoint and the write-ahead log after it, and rolls a new checkpoint, which
+// truncates the log.  Afterwards, every commit is appended to the log, and every 1024 commits a
+// new checkpoint is rolled in the background.  A failed background checkpoint is retried
+// 1024 commits later; the log still holds all commits in the meantime.  Closing the map
+// closes the files.
+func Open[K comparable, V any](path string, opts ...lrmap.Option[K, V]) (*lrmap.LRMap[K, V], error) {
+	f, err := OpenFile[K, V](path)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		m              *lrmap.LRMap[K, V]
+		checkpointing  atomic.Bool
+		checkpointFrom atomic.Uint64
+	)
+
+	rollCheckpoint := func(gen uint64) {
+		if gen-checkpointFrom.Load() < checkpointEvery || !checkpointing.CompareAndSwap(false, true) {
+			return
+		}
+
+		go func() {
+			defer checkpointing.Store(false)
+
+			if m.Checkpoint() == nil {
+				checkpointFrom.Store(gen)
+			}
+		}()
+	}
+
+	opts = append(opts, lrmap.WithPersister[K, V](f), lrmap.WithPostCommit[K, V](rollCheckpoint))
+	m = lrmap.New(opts...)
+
+	if err := m.Restore(); err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	if err := m.Checkpoint(); err != nil {
+		_ = f.Close()
+
+		return nil, err
+	}
+
+	checkpointFrom.Store(m.Stats().Generation)
+
+	return m, nil
+}
@@ -0,0 +1,35 @@
+package persist
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	m, err := Open[int, string](dir)
+	if err != nil {
+		t.Fatalf("Open(): %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		m.Set(i, "value")
+		m.Commit()
+	}
+
+	reopened, err := Open[int, string](dir)
+	if err != nil {
+		t.Fatalf("Open() again: %v", err)
+	}
+
+	if s := reopened.Stats(); s.CommittedLen != 10 || s.Generation != 10 {
+		t.Errorf("reopened map has %d entries of generation %d, want 10 of 10", s.CommittedLen, s.Generation)
+	}
+
+	// the checkpoint after restoring has made the log obsolete
+	if fi, err := os.Stat(filepath.Join(dir, walFile)); err != nil || fi.Size() != 0 {
+		t.Errorf("write-ahead log has not been truncated: %v, %v", fi, err)
+	}
+}
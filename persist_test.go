@@ -0,0 +1,88 @@
+package lrmap
+
+import (
+	"errors"
+	"iter"
+	"testing"
+)
+
+type memPersister[K comparable, V any] struct {
+	checkpoint CommitBatch[K, V]
+	log        []CommitBatch[K, V]
+	fail       error
+}
+
+func (p *memPersister[K, V]) AppendOps(batch CommitBatch[K, V]) error {
+	if p.fail != nil {
+		return p.fail
+	}
+
+	p.log = append(p.log, batch)
+
+	return nil
+}
+
+func (p *memPersister[K, V]) WriteCheckpoint(gen uint64, entries iter.Seq2[K, V]) error {
+	p.checkpoint = CommitBatch[K, V]{Generation: gen}
+
+	for key, value := range entries {
+		p.checkpoint.Ops = append(p.checkpoint.Ops, Op[K, V]{Kind: OpSet, Key: key, Value: value})
+	}
+
+	return nil
+}
+
+func (p *memPersister[K, V]) Load(fn func(batch CommitBatch[K, V]) error) error {
+	if err := fn(p.checkpoint); err != nil {
+		return err
+	}
+
+	for _, batch := range p.log {
+		if batch.Generation > p.checkpoint.Generation {
+			if err := fn(batch); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func TestPersister(t *testing.T) {
+	p := new(memPersister[string, int])
+	lrm := New(WithPersister[string, int](p))
+
+	lrm.Set("a", 1)
+	lrm.Commit()
+
+	if err := lrm.Checkpoint(); err != nil {
+		t.Fatalf("Checkpoint(): %v", err)
+	}
+
+	lrm.Set("b", 2)
+	lrm.Commit()
+
+	errDisk := errors.New("disk full")
+	p.fail = errDisk
+
+	lrm.Set("c", 3)
+
+	if err := lrm.TryCommit(); !errors.Is(err, errDisk) {
+		t.Errorf("TryCommit() with failing persister, want %v, got %v", errDisk, err)
+	}
+
+	p.fail = nil
+
+	restored := New(WithPersister[string, int](p))
+	if err := restored.Restore(); err != nil {
+		t.Fatalf("Restore(): %v", err)
+	}
+
+	if want := map[string]int{"a": 1, "b": 2}; !restored.EqualCommitted(want, func(a, b int) bool { return a == b }) {
+		t.Errorf("restored %v, want %v", restored, want)
+	}
+
+	if len(p.log) != 2 {
+		t.Errorf("Restore() has persisted the restored state again: %v", p.log)
+	}
+}
@@ -0,0 +1,92 @@
+package lrmap
+
+import "sync"
+
+// pipeline is the state of a pipelined commit, see WithPipelinedCommits.
+type pipeline[K comparable, V any] struct {
+	// done is signaled with m.mu when a commit has finished.
+	done *sync.Cond
+
+	// inFlight is set while a commit waits for readers without holding m.mu.
+	inFlight bool
+
+	// deferred are the writes made while a commit is in flight.
+	deferred []operation[K, V]
+}
+
+// WithPipelinedCommits makes Commit release the writer lock while it waits for the readers of
+// the former read map, so that Set and Delete do not block for the entire commit when readers
+// are slow.  Writes made in the meantime are recorded in a second redo log and applied (and
+// logged for the next commit) as soon as the commit has replayed the first one.  All other
+// writer methods, including reads of the write map, still wait for the commit to finish, as do
+// writes to a map with a reducer or a memory budget, since those depend on the previous value.
+//
+// The reader barrier (see WithReaderBarrier) is called without the writer lock.  Commits of a
+// CommitGroup and Freeze are not pipelined.
+func WithPipelinedCommits[K comparable, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.pipeline = &pipeline[K, V]{done: sync.NewCond(&m.mu), inFlight: false, deferred: nil}
+	}
+}
+
+// awaitCommit waits until no pipelined commit is in flight.  The caller must hold m.mu.
+func (m *LRMap[K, V]) awaitCommit() {
+	if m.pipeline == nil {
+		return
+	}
+
+	for m.pipeline.inFlight {
+		m.pipeline.done.Wait()
+	}
+}
+
+// deferring reports whether writes must be deferred.  The caller must hold m.mu.
+func (m *LRMap[K, V]) deferring() bool {
+	return m.pipeline != nil && m.pipeline.inFlight && m.reducer == nil && m.budget.limit == 0
+}
+
+// deferWrite records a write while a commit is in flight.  The caller must hold m.mu.
+func (m *LRMap[K, V]) deferWrite(typ OpKind, key K, value V) error {
+	if err := m.writable(); err != nil {
+		return err
+	}
+
+	key = m.normalize(key)
+
+	if typ == OpSet {
+		if err := m.validate(key, value); err != nil {
+			return err
+		}
+	}
+
+	m.pipeline.deferred = append(m.pipeline.deferred, operation[K, V]{typ: typ, key: key, value: value})
+
+	return nil
+}
+
+// finishPipelined is finishCommit, but releases m.mu while it waits for readers.  The caller
+// must hold m.mu.
+func (m *LRMap[K, V]) finishPipelined() {
+	p := m.pipeline
+	p.inFlight = true
+
+	m.mu.Unlock()
+	report := m.awaitStale()
+	m.mu.Lock()
+
+	m.lastWait = report
+	m.syncStale()
+
+	for _, op := range p.deferred {
+		if op.typ == OpSet {
+			m.set(op.key, op.value)
+		} else {
+			m.delete(op.key)
+		}
+	}
+
+	clear(p.deferred)
+	p.deferred = p.deferred[:0]
+	p.inFlight = false
+	p.done.Broadcast()
+}
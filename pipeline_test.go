@@ -0,0 +1,148 @@
+package lrmap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestPipelinedCommits(t *testing.T) {
+	blocked := make(chan int, 1)
+	lrm := New(
+		WithPipelinedCommits[int, int](),
+		WithReaderBarrier[int, int](func(stragglers int) {
+			if stragglers > 0 {
+				blocked <- stragglers
+			}
+		}),
+	)
+
+	lrm.Set(1, 1)
+	lrm.Set(2, 2)
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set(3, 3)
+	rh.Enter()
+
+	done := make(chan struct{})
+
+	go func() {
+		lrm.Commit()
+		close(done)
+	}()
+
+	<-blocked
+
+	// the commit waits for rh, but writes go through
+	lrm.Set(4, 4)
+	lrm.Delete(1)
+
+	select {
+	case <-done:
+		t.Fatal("commit has not waited for the reader")
+	default:
+	}
+
+	// rh still sees the view it has entered
+	if v, ok := rh.GetOK(3); ok || rh.Get(2) != 2 {
+		t.Errorf("reader: Get(3), want the former view, got %d", v)
+	}
+
+	rh.Leave()
+	<-done
+
+	// the deferred writes are visible to the writer and published by the next commit
+	if _, ok := lrm.GetOK(1); ok {
+		t.Errorf("writer: deferred Delete(1) not applied")
+	}
+
+	if v := lrm.Get(4); v != 4 {
+		t.Errorf("writer: Get(4), want 4, got %d", v)
+	}
+
+	rh.Enter()
+
+	if v, ok := rh.GetOK(4); ok {
+		t.Errorf("reader: deferred Set(4) visible before the commit: %d", v)
+	}
+
+	rh.Leave()
+
+	lrm.Commit()
+	lrm.Commit()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	want := map[int]int{2: 2, 3: 3, 4: 4}
+	if !rh.Equal(want, func(a, b int) bool { return a == b }) {
+		t.Errorf("after committing the deferred writes, want %v", want)
+	}
+
+	if s := lrm.Stats(); s.Len != 3 || s.PendingOps != 0 {
+		t.Errorf("Stats(), want 3 entries and no pending ops, got %+v", s)
+	}
+}
+
+func TestPipelinedCommitsConcurrent(t *testing.T) {
+	lrm := New(WithPipelinedCommits[int, int]())
+
+	var wg sync.WaitGroup
+
+	stop := make(chan struct{})
+
+	for r := 0; r < 4; r++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			rh := lrm.NewReadHandler()
+			defer rh.Close()
+
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				rh.Enter()
+				for k := 0; k < 10; k++ {
+					if v, ok := rh.GetOK(k); ok && v%10 != k {
+						t.Errorf("Get(%d) = %d", k, v)
+					}
+				}
+				rh.Leave()
+			}
+		}()
+	}
+
+	committed := make(chan struct{})
+
+	go func() {
+		defer close(committed)
+
+		for i := 0; i < 20; i++ {
+			lrm.Commit()
+		}
+	}()
+
+	for i := 0; i < 2000; i++ {
+		lrm.Set(i%10, i)
+	}
+
+	<-committed
+	close(stop)
+	wg.Wait()
+
+	lrm.Commit()
+
+	for k := 0; k < 10; k++ {
+		if v := lrm.Get(k); v != 1990+k {
+			t.Errorf("Get(%d), want %d, got %d", k, 1990+k, v)
+		}
+	}
+}
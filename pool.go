@@ -0,0 +1,21 @@
+package lrmap
+
+// readerPool sizes the free list of recycled read handlers, see WithReaderPool.
+type readerPool struct {
+	min, max int
+}
+
+// WithReaderPool keeps recycled read handlers in a free list of up to max handlers instead of a
+// sync.Pool, and creates min handlers up front, so that NewReadHandler on a latency sensitive
+// path does not have to create one.  Unlike a sync.Pool, the free list is not emptied by the
+// GC.  Handlers recycled into a full free list are closed.  A max of zero disables pooling:
+// every NewReadHandler creates a handler, and Recycle closes it.
+func WithReaderPool[K comparable, V any](min, max int) Option[K, V] {
+	if min > max {
+		panic("illegal use: WithReaderPool() requires min <= max")
+	}
+
+	return func(m *LRMap[K, V]) {
+		m.readerPool = &readerPool{min: min, max: max}
+	}
+}
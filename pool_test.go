@@ -0,0 +1,48 @@
+package lrmap
+
+import "testing"
+
+func TestReaderPool(t *testing.T) {
+	lrm := New(WithReaderPool[int, int](2, 3))
+
+	if n := len(lrm.freeHandlers); n != 2 {
+		t.Fatalf("want 2 pre-warmed handlers, got %d", n)
+	}
+
+	handlers := make([]*ReadHandler[int, int], 5)
+	for i := range handlers {
+		handlers[i] = lrm.NewReadHandler()
+	}
+
+	if n := countSlots(&lrm.readHandlers); n != 5 {
+		t.Errorf("want 5 handlers in use, got %d", n)
+	}
+
+	for _, rh := range handlers {
+		rh.Recycle()
+	}
+
+	// the free list keeps 3 handlers and closes the others
+	if n := len(lrm.freeHandlers); n != 3 {
+		t.Errorf("want 3 pooled handlers, got %d", n)
+	}
+
+	if n := countSlots(&lrm.readHandlers); n != 3 {
+		t.Errorf("want 3 handlers left, got %d", n)
+	}
+}
+
+func TestReaderPoolDisabled(t *testing.T) {
+	lrm := New(WithReaderPool[int, int](0, 0))
+
+	rh := lrm.NewReadHandler()
+	rh.Recycle()
+
+	if n := countSlots(&lrm.readHandlers); n != 0 {
+		t.Errorf("want recycled handler closed, got %d handlers", n)
+	}
+
+	if rh2 := lrm.NewReadHandler(); rh2 == rh {
+		t.Errorf("want a new handler without pooling")
+	}
+}
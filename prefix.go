@@ -0,0 +1,80 @@
+package lrmap
+
+import "strings"
+
+// WithDeletePrefix enables prefix deletions on a map before any call to DeletePrefix, which
+// ApplyOps requires to apply OpDeletePrefix operations.
+func WithDeletePrefix[K ~string, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.hasPrefix = hasPrefix[K]
+	}
+}
+
+// DeletePrefix deletes all keys that start with prefix and returns how many it deleted.  It
+// records a single operation in the redo log, which Commit replays by scanning the other arena,
+// so dropping a large namespace does not log a deletion per key.  With WithRedoCompaction or
+// WithReplayChunk, which keep track of operations by key, it logs the deletions one by one.
+//
+// DeletePrefix is a function rather than a method, since it constrains the key type.
+func DeletePrefix[K ~string, V any](m *LRMap[K, V], prefix string) int {
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.writable() != nil {
+		return 0
+	}
+
+	m.hasPrefix = hasPrefix[K]
+
+	return m.deletePrefix(K(prefix))
+}
+
+// deletePrefix deletes the keys with prefix from the write map.  The caller must hold m.mu.
+func (m *LRMap[K, V]) deletePrefix(prefix K) int {
+	m.syncAll()
+
+	data := m.writeMap.Load().data
+	keys := m.prefixed(data, prefix)
+
+	if m.redoIndex != nil || m.replayChunk > 0 {
+		for _, key := range keys {
+			m.delete(key)
+		}
+
+		return len(keys)
+	}
+
+	var zero V
+
+	for _, key := range keys {
+		m.written(data, key, zero, false)
+		data.Delete(key)
+	}
+
+	if len(keys) > 0 {
+		// nolint:exhaustivestruct
+		m.log(operation[K, V]{typ: OpDeletePrefix, key: prefix})
+	}
+
+	return len(keys)
+}
+
+// prefixed returns the keys of data that start with prefix.  Arenas need not support deletion
+// while iterating, so the keys are collected first.
+func (m *LRMap[K, V]) prefixed(data Arena[K, V], prefix K) []K {
+	var keys []K
+
+	data.Iterate(func(key K, _ V) bool {
+		if m.hasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+
+		return true
+	})
+
+	return keys
+}
+
+func hasPrefix[K ~string](key, prefix K) bool {
+	return strings.HasPrefix(string(key), string(prefix))
+}
@@ -0,0 +1,76 @@
+package lrmap
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestDeletePrefix(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option[string, int]
+		ops  int
+	}{
+		{name: "default", ops: 1},
+		{name: "compaction", opts: []Option[string, int]{WithRedoCompaction[string, int]()}, ops: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lrm := New(append(tc.opts, WithDiff[string, int](nil))...)
+
+			for i, key := range []string{"tenant/a/x", "tenant/a/y", "tenant/ab", "tenant/b/x"} {
+				lrm.Set(key, i)
+			}
+
+			lrm.Commit()
+
+			if n := DeletePrefix(lrm, "tenant/a"); n != 3 {
+				t.Errorf("DeletePrefix(): want 3, got %d", n)
+			}
+
+			if n := len(lrm.redoLog); n != tc.ops {
+				t.Errorf("redo log: want %d operations, got %d", tc.ops, n)
+			}
+
+			lrm.Commit()
+
+			removed := lrm.LastDiff().Removed
+			slices.Sort(removed)
+
+			if want := []string{"tenant/a/x", "tenant/a/y", "tenant/ab"}; !slices.Equal(removed, want) {
+				t.Errorf("LastDiff().Removed: want %v, got %v", want, removed)
+			}
+
+			// publish the replayed arena
+			lrm.Set("other", 0)
+			lrm.Commit()
+
+			rh := lrm.NewReadHandler()
+			defer rh.Close()
+
+			rh.Enter()
+			defer rh.Leave()
+
+			if keys := rh.AppendKeys(nil); len(keys) != 2 || !rh.Contains("tenant/b/x") {
+				t.Errorf("want tenant/b/x and other left, got %v", keys)
+			}
+		})
+	}
+}
+
+func TestApplyOpsDeletePrefix(t *testing.T) {
+	ops := []Op[string, int]{{Kind: OpSet, Key: "a/1", Value: 1}, {Kind: OpDeletePrefix, Key: "a/"}}
+
+	if err := New[string, int]().ApplyOps(ops); !errors.Is(err, ErrUnsupportedOp) {
+		t.Errorf("ApplyOps() without WithDeletePrefix: want ErrUnsupportedOp, got %v", err)
+	}
+
+	lrm := New(WithDeletePrefix[string, int]())
+	if err := lrm.ApplyOps(ops); err != nil {
+		t.Fatalf("ApplyOps(): %v", err)
+	}
+
+	if lrm.Contains("a/1") {
+		t.Errorf("want a/1 deleted by prefix")
+	}
+}
@@ -0,0 +1,73 @@
+package lrmap
+
+import "runtime"
+
+// ReadTx enters read handlers of several maps of a CommitGroup consistently: all of them see
+// the state before a group commit, or all of them see the state after it.  Commits of single
+// maps of the group are not coordinated.  Like a ReadHandler, a ReadTx must be used by a single
+// goroutine.
+type ReadTx struct {
+	group   *CommitGroup
+	readers []txReader
+}
+
+type txReader interface {
+	Enter()
+	Leave()
+	Close()
+}
+
+func (g *CommitGroup) NewReadTx() *ReadTx {
+	return &ReadTx{group: g} // nolint:exhaustivestruct
+}
+
+// TxReader returns a read handler for m that tx enters and leaves.  m must be a member of the
+// group of tx, and tx must not be entered.  The handler must not be entered, left, or closed
+// by itself.
+func TxReader[K comparable, V any](tx *ReadTx, m *LRMap[K, V]) *ReadHandler[K, V] {
+	if m.group != tx.group {
+		panic("illegal use: map is not a member of the commit group of the transaction")
+	}
+
+	rh := m.NewReadHandler()
+	tx.readers = append(tx.readers, rh)
+
+	return rh
+}
+
+// Enter enters all read handlers of tx.  If a group commit publishes while Enter is entering
+// the handlers, Enter starts over.
+func (tx *ReadTx) Enter() {
+	for {
+		seq := tx.group.seq.Load()
+		if seq%2 == 1 {
+			runtime.Gosched()
+
+			continue
+		}
+
+		for _, r := range tx.readers {
+			r.Enter()
+		}
+
+		if tx.group.seq.Load() == seq {
+			return
+		}
+
+		tx.Leave()
+	}
+}
+
+func (tx *ReadTx) Leave() {
+	for _, r := range tx.readers {
+		r.Leave()
+	}
+}
+
+func (tx *ReadTx) Close() {
+	for _, r := range tx.readers {
+		r.Close()
+	}
+
+	tx.readers = nil
+}
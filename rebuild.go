@@ -0,0 +1,80 @@
+package lrmap
+
+import "maps"
+
+const (
+	// defaultRebuildFactor is the factor by which the redo log must outgrow the map before
+	// Commit rebuilds the other arena instead of replaying the log.
+	defaultRebuildFactor = 4
+
+	// minRebuildOps keeps Commit from rebuilding small maps, whose arenas would be reallocated
+	// over and over for a handful of operations.
+	minRebuildOps = 1024
+)
+
+// WithRebuildFactor makes Commit rebuild the other arena by copying the published one instead
+// of replaying the redo log, if the log holds more than k operations per entry of the map
+// (bulk reloads, counters hammered between commits).  Rebuilding is linear in the size of the
+// map, and it does not carry over the garbage the replaced arena has accumulated.  The
+// default is 4; zero disables rebuilding.
+func WithRebuildFactor[K comparable, V any](k int) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.rebuildFactor = k
+	}
+}
+
+// shouldRebuild reports whether rebuilding the write map is cheaper than replaying the redo
+// log, or whether LoadSorted has asked for it.
+func (m *LRMap[K, V]) shouldRebuild() bool {
+	if m.rebuildNext {
+		return true
+	}
+
+	n := len(m.redoLog)
+
+	return m.rebuildFactor > 0 && n >= minRebuildOps && n > m.rebuildFactor*m.readMap.Load().data.Len()
+}
+
+// rebuild replaces the write map with a copy of the read map.  The caller must hold m.mu, and
+// no reader may use the write map.
+//
+// A MapArena is pre-sized for the most entries the map has held at any commit, so that a map
+// that cycles through load and clear phases does not grow its buckets from scratch each time.
+func (m *LRMap[K, V]) rebuild() {
+	published := m.readMap.Load().data
+
+	if m.entryMeta {
+		m.writeMap.Load().meta = maps.Clone(m.readMap.Load().meta)
+	}
+
+	data, sized := m.sizedArena(max(published.Len(), m.highWater))
+	if m.copier == nil && !sized {
+		m.writeMap.Load().data = published.Clone()
+
+		return
+	}
+
+	copier := m.copier
+	if copier == nil {
+		copier = func(value V) V { return value }
+	}
+
+	published.Iterate(func(key K, value V) bool {
+		data.Set(key, copier(value))
+
+		return true
+	})
+
+	m.writeMap.Load().data = data
+}
+
+// sizedArena returns a new arena, which is pre-sized for n entries if it is a MapArena.
+func (m *LRMap[K, V]) sizedArena(n int) (Arena[K, V], bool) {
+	data := m.newArena()
+
+	if a, ok := data.(MapArena[K, V]); ok && len(a) == 0 && n > 0 {
+		return make(MapArena[K, V], n), true
+	}
+
+	return data, false
+}
@@ -0,0 +1,97 @@
+package lrmap
+
+import "testing"
+
+func TestRebuild(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		opts    []Option[int, []int]
+		rebuild bool
+	}{
+		{name: "default", rebuild: true},
+		{name: "copier", opts: []Option[int, []int]{WithValueCopier[int](cloneInts)}, rebuild: true},
+		{name: "disabled", opts: []Option[int, []int]{WithRebuildFactor[int, []int](0)}, rebuild: false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			lrm := New(tc.opts...)
+
+			// 10 keys, but many more operations
+			for i := 0; i < 2*minRebuildOps; i++ {
+				lrm.Set(i%10, []int{i})
+			}
+
+			lrm.Delete(0)
+
+			lrm.mu.Lock()
+			lrm.publish()
+
+			if got := lrm.shouldRebuild(); got != tc.rebuild {
+				t.Errorf("shouldRebuild(): want %t, got %t", tc.rebuild, got)
+			}
+
+			lrm.finishCommit()
+			lrm.completeCommit()
+
+			// the rebuilt side is published next, so it must hold the same entries
+			lrm.Set(10, []int{10})
+			lrm.Commit()
+
+			rh := lrm.NewReadHandler()
+			defer rh.Close()
+
+			rh.Enter()
+			defer rh.Leave()
+
+			if got := rh.Len(); got != 10 {
+				t.Errorf("Len(): want 10, got %d", got)
+			}
+
+			if got := rh.Get(10); len(got) != 1 || got[0] != 10 {
+				t.Errorf("Get(10): want [10], got %v", got)
+			}
+
+			for key := 1; key < 10; key++ {
+				// the last value Set for key
+				want := (2*minRebuildOps-1-key)/10*10 + key
+
+				if got := rh.Get(key); len(got) != 1 || got[0] != want {
+					t.Errorf("Get(%d): want [%d], got %v", key, want, got)
+				}
+			}
+		})
+	}
+}
+
+func cloneInts(s []int) []int { return append([]int(nil), s...) }
+
+func TestRebuildHighWater(t *testing.T) {
+	lrm := New[int, int]()
+
+	for i := 0; i < 100; i++ {
+		lrm.Set(i, i)
+	}
+
+	lrm.Commit()
+
+	for i := 0; i < 100; i++ {
+		lrm.Delete(i)
+	}
+
+	lrm.Commit()
+
+	if s := lrm.Stats(); s.HighWaterLen != 100 || s.CommittedLen != 0 {
+		t.Errorf("Stats() after clearing, want high water 100 and nothing committed, got %+v", s)
+	}
+
+	// the next load rebuilds the other arena, pre-sized for the high water mark
+	for i := 0; i < 2*minRebuildOps; i++ {
+		lrm.Set(i%50, i)
+	}
+
+	lrm.Commit()
+	lrm.Commit()
+
+	if s := lrm.Stats(); s.HighWaterLen != 100 || s.CommittedLen != 50 || s.Len != 50 {
+		t.Errorf("Stats() after reloading, want high water 100 and 50 entries, got %+v", s)
+	}
+}
@@ -0,0 +1,120 @@
+package lrmap
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+const (
+	// cacheLineSize is the assumed size of a cache line.
+	cacheLineSize = 64
+
+	// epochStride is the distance between two epochs.  Adjacent-line prefetchers fetch cache
+	// lines in aligned pairs, so each epoch gets two lines to itself, so that readers bumping
+	// their epochs do not invalidate each other's lines, nor those the committer scans.
+	epochStride = 2 * cacheLineSize
+
+	slotsPerSegment = 16
+)
+
+type (
+	// registry keeps track of the epochs of all read handlers of a map.  It is a linked list
+	// of fixed-size segments of slots, which grows lock-free and never shrinks; slots of
+	// closed handlers are reused.  Scanning the registry walks a few contiguous arrays instead
+	// of chasing a pointer per handler.
+	registry struct {
+		head atomic.Pointer[slotSegment]
+	}
+
+	// slotSegment keeps the epochs of its slots apart from the slots in an array without
+	// pointers, which it aligns itself: the allocator does not align objects to cache lines,
+	// and those with pointers not even to their size class.
+	slotSegment struct {
+		slots  [slotsPerSegment]epochSlot
+		epochs []atomic.Uint64
+		next   atomic.Pointer[slotSegment]
+	}
+
+	// epochSlot holds the epoch of a single read handler.  The epoch is odd while the handler
+	// is entered.  Epochs are never reset, so a slot is only released while its epoch is even.
+	//
+	// Readers write their epoch on every Enter and Leave, but the other fields rarely change,
+	// so slots are packed, and only the epochs are padded.
+	epochSlot struct {
+		epoch *atomic.Uint64
+		owner atomic.Pointer[ReaderInfo]
+		used  atomic.Bool
+
+		// abandoned is the epoch in which a commit has stopped waiting for the handler (see
+		// WithReaderWatchdog).  The handler must not read until it leaves that epoch.
+		abandoned atomic.Uint64
+
+		// enteredBy records the goroutine that has entered the handler last, which is only
+		// tracked in debug mode.
+		enteredBy atomic.Pointer[enteredBy]
+	}
+)
+
+// acquire returns an unused slot, adding a segment if all slots are in use.
+func (r *registry) acquire() *epochSlot {
+	for seg := grow(&r.head); ; {
+		for i := range seg.slots {
+			if slot := &seg.slots[i]; !slot.used.Load() && slot.used.CompareAndSwap(false, true) {
+				return slot
+			}
+		}
+
+		seg = grow(&seg.next)
+	}
+}
+
+// grow returns the segment link points to, adding one if there is none.
+func grow(link *atomic.Pointer[slotSegment]) *slotSegment {
+	if seg := link.Load(); seg != nil {
+		return seg
+	}
+
+	seg := newSlotSegment()
+	if !link.CompareAndSwap(nil, seg) {
+		seg = link.Load()
+	}
+
+	return seg
+}
+
+func newSlotSegment() *slotSegment {
+	const stride = epochStride / 8
+
+	seg := new(slotSegment)
+
+	// one spare stride to align the first epoch
+	seg.epochs = make([]atomic.Uint64, (slotsPerSegment+1)*stride)
+	skip := (epochStride - uintptr(unsafe.Pointer(&seg.epochs[0]))%epochStride) % epochStride / 8
+
+	for i := range seg.slots {
+		seg.slots[i].epoch = &seg.epochs[int(skip)+i*stride]
+	}
+
+	return seg
+}
+
+// release returns slot to the registry.  A handler that is closed (or cleaned up) while
+// entered would stall every commit that waits for it, so release leaves on its behalf.
+func (r *registry) release(slot *epochSlot) {
+	if slot.epoch.Load()%2 == 1 {
+		slot.epoch.Add(1)
+	}
+
+	slot.used.Store(false)
+}
+
+// forEach calls fn for all slots in use.
+func (r *registry) forEach(fn func(*epochSlot)) {
+	for seg := r.head.Load(); seg != nil; seg = seg.next.Load() {
+		for i := range seg.slots {
+			if slot := &seg.slots[i]; slot.used.Load() {
+				fn(slot)
+			}
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package lrmap
+
+import (
+	"sync"
+	"testing"
+	"unsafe"
+)
+
+func TestEpochPadding(t *testing.T) {
+	var r registry
+
+	for i := 0; i < 3*slotsPerSegment; i++ {
+		if addr := uintptr(unsafe.Pointer(r.acquire().epoch)); addr%epochStride != 0 {
+			t.Fatalf("epoch %d at %#x is not aligned to %d", i, addr, epochStride)
+		}
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	var (
+		r     registry
+		mu    sync.Mutex
+		slots = make(map[*epochSlot]bool)
+		wg    sync.WaitGroup
+	)
+
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			slot := r.acquire()
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if slots[slot] {
+				t.Errorf("slot %p acquired twice", slot)
+			}
+
+			slots[slot] = true
+		}()
+	}
+
+	wg.Wait()
+
+	n := 0
+	r.forEach(func(*epochSlot) { n++ })
+
+	if n != 100 {
+		t.Errorf("forEach visited %d slots, want 100", n)
+	}
+
+	for slot := range slots {
+		r.release(slot)
+	}
+
+	if slot := r.acquire(); slot != &r.head.Load().slots[0] {
+		t.Errorf("released slots are not reused")
+	}
+}
+
+func BenchmarkCommitManyHandlers(b *testing.B) {
+	lrm := New[int, int]()
+
+	handlers := make([]*ReadHandler[int, int], 4096)
+	for i := range handlers {
+		handlers[i] = lrm.NewReadHandler()
+	}
+
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		lrm.Set(i, i)
+		lrm.Commit()
+	}
+
+	b.StopTimer()
+
+	for _, rh := range handlers {
+		rh.Close()
+	}
+}
+
+// BenchmarkEnterLeaveParallel measures readers entering and leaving their handlers
+// concurrently while a writer commits, e.g. with -cpu 64 on machines with many cores.
+func BenchmarkEnterLeaveParallel(b *testing.B) {
+	lrm := New[int, int]()
+	lrm.Set(1, 1)
+	lrm.Commit()
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		for i := 0; ; i++ {
+			select {
+			case <-stop:
+				return
+			default:
+				lrm.Set(i%64, i)
+				lrm.Commit()
+			}
+		}
+	}()
+
+	b.RunParallel(func(pb *testing.PB) {
+		rh := lrm.NewReadHandler()
+		defer rh.Close()
+
+		for pb.Next() {
+			rh.Enter()
+			_ = rh.Get(1)
+			rh.Leave()
+		}
+	})
+
+	close(stop)
+	<-done
+}
@@ -0,0 +1,102 @@
+package lrmap
+
+// WithReplayChunk caps how much of the redo log Commit replays while holding the writer lock.
+// If the redo log holds more than n operations, Commit replays the last operation of up to n
+// keys and leaves the rest as a backlog, which subsequent writes work off by n keys each.
+// Before a key is read or written, its backlog is replayed, so that the writer always sees
+// a consistent state.  Operations that look at the whole write map (e.g. DeleteFunc, Stats,
+// String) and the next Commit replay the complete backlog first.
+func WithReplayChunk[K comparable, V any](n int) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.replayChunk = n
+	}
+}
+
+// startReplay turns the redo log into the backlog and replays its first chunk.  The backlog
+// keeps a single operation per key, which the operations on that key are merged into.
+func (m *LRMap[K, V]) startReplay() {
+	m.backlog = make(map[K]operation[K, V], len(m.redoLog))
+
+	for _, op := range m.redoLog {
+		if prev, ok := m.backlog[op.key]; ok {
+			op = m.merge(prev, op)
+		}
+
+		m.backlog[op.key] = op
+	}
+
+	m.replaySome()
+}
+
+// replaySome replays up to one chunk of the backlog.
+func (m *LRMap[K, V]) replaySome() {
+	if len(m.backlog) == 0 {
+		return
+	}
+
+	n := m.replayChunk
+
+	for key, op := range m.backlog {
+		if n == 0 {
+			return
+		}
+
+		delete(m.backlog, key)
+		m.apply(op)
+		n--
+	}
+
+	m.backlog = nil
+}
+
+// syncKey replays the backlog of key.
+func (m *LRMap[K, V]) syncKey(key K) {
+	if len(m.backlog) == 0 {
+		return
+	}
+
+	if op, ok := m.backlog[key]; ok {
+		delete(m.backlog, key)
+		m.apply(op)
+	}
+}
+
+// syncAll replays the complete backlog.
+func (m *LRMap[K, V]) syncAll() {
+	for _, op := range m.backlog {
+		m.apply(op)
+	}
+
+	m.backlog = nil
+}
+
+// WithRedoCompaction keeps only one (merged) operation per key in the redo log, so that
+// replay cost and retained memory scale with the number of distinct keys written between
+// commits instead of the number of writes.  Pre-commit hooks see the compacted log.
+func WithRedoCompaction[K comparable, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.redoIndex = make(map[K]int)
+	}
+}
+
+// defaultRedoLogRetain is the capacity (in operations) up to which Commit keeps the backing
+// array of the redo log for reuse.
+const defaultRedoLogRetain = 1 << 12
+
+// WithRedoLogRetention sets the capacity (in operations) up to which Commit keeps the backing
+// array of the redo log for the next round of writes.  Larger logs are dropped, so that a
+// burst of writes does not pin a huge array forever.  Zero disables reuse.
+func WithRedoLogRetention[K comparable, V any](n int) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.redoLogRetain = n
+	}
+}
+
+// WithRedoLogCapacity pre-allocates the redo log for n operations and retains that capacity
+// across commits, so that up to n writes between two commits do not allocate for the log.
+func WithRedoLogCapacity[K comparable, V any](n int) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.redoLog = make([]operation[K, V], 0, n)
+		m.redoLogRetain = max(m.redoLogRetain, n)
+	}
+}
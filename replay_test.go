@@ -0,0 +1,120 @@
+package lrmap
+
+import "testing"
+
+func TestReplayChunk(t *testing.T) {
+	lrm := New(WithReplayChunk[int, int](10))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	for i := 0; i < 100; i++ {
+		lrm.Set(i, i)
+	}
+
+	lrm.Commit()
+
+	if n := len(lrm.backlog); n != 90 {
+		t.Fatalf("want a backlog of 90 keys after Commit, got %d", n)
+	}
+
+	// reads and writes of keys in the backlog must see the committed state
+	if v, ok := lrm.GetOK(50); !ok || v != 50 {
+		t.Errorf("GetOK(50), want (50, true), got (%d, %t)", v, ok)
+	}
+
+	lrm.Set(60, -60)
+	lrm.Delete(70)
+
+	if n := len(lrm.backlog); n >= 90-2 {
+		t.Errorf("writes did not work off the backlog, %d keys left", n)
+	}
+
+	lrm.Commit()
+	lrm.Commit()
+
+	rh.Enter()
+	for i := 0; i < 100; i++ {
+		want, wantOK := i, true
+
+		switch i {
+		case 60:
+			want = -60
+		case 70:
+			want, wantOK = 0, false
+		}
+
+		if v, ok := rh.GetOK(i); v != want || ok != wantOK {
+			t.Errorf("GetOK(%d), want (%d, %t), got (%d, %t)", i, want, wantOK, v, ok)
+		}
+	}
+	rh.Leave()
+
+	if s := lrm.Stats(); s.Len != 99 || len(lrm.backlog) != 0 {
+		t.Errorf("Stats(), want Len 99 and no backlog, got %+v and %d", s, len(lrm.backlog))
+	}
+}
+
+func TestRedoCompaction(t *testing.T) {
+	lrm := New(WithRedoCompaction[string, int]())
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	for i := 0; i < 100; i++ {
+		lrm.Set("counter", i)
+	}
+
+	lrm.Set("gone", 1)
+	lrm.Delete("gone")
+
+	if s := lrm.Stats(); s.PendingOps != 2 || s.SupersededOps != 0 {
+		t.Errorf("Stats(), want 2 pending and no superseded operations, got %+v", s)
+	}
+
+	lrm.Commit()
+	lrm.Set("counter", 100)
+	lrm.Commit()
+
+	rh.Enter()
+	if v := rh.Get("counter"); v != 100 || rh.Contains("gone") {
+		t.Errorf("want counter 100 and no gone, got %s", rh)
+	}
+	rh.Leave()
+
+	lrm.Commit()
+
+	rh.Enter()
+	if v := rh.Get("counter"); v != 100 || rh.Len() != 1 {
+		t.Errorf("other arena out of sync: %s", rh)
+	}
+	rh.Leave()
+}
+
+func TestRedoLogRetention(t *testing.T) {
+	lrm := New(WithRedoLogRetention[int, int](64))
+
+	for i := 0; i < 10; i++ {
+		lrm.Set(i, i)
+	}
+
+	lrm.Commit()
+
+	if len(lrm.redoLog) != 0 || cap(lrm.redoLog) == 0 {
+		t.Errorf("want an empty redo log with retained capacity, got len %d, cap %d", len(lrm.redoLog), cap(lrm.redoLog))
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { lrm.Delete(1) }); allocs != 0 {
+		t.Errorf("Delete() into a retained redo log, want 0 allocs, got %f", allocs)
+	}
+
+	for i := 0; i < 100; i++ {
+		lrm.Set(i, i)
+	}
+
+	lrm.Commit()
+
+	if lrm.redoLog != nil {
+		t.Errorf("want a dropped redo log after exceeding the retention, got cap %d", cap(lrm.redoLog))
+	}
+}
@@ -0,0 +1,81 @@
+package lrmap
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+)
+
+// ErrReplicaGap is returned by Replica.Apply for a batch that does not follow the last one.
+var ErrReplicaGap = errors.New("replica: batch does not follow the last applied one")
+
+// Replica is a read-only copy of an LRMap that follows the commit batches of the primary (see
+// Subscribe), e.g. after they have been sent over the network.  Each batch is committed on its
+// own, so that readers of the replica see the same states as readers of the primary.  Apply
+// and Follow must be called by one goroutine at a time; the other methods may be called
+// concurrently with them.
+type Replica[K comparable, V any] struct {
+	lrmap *LRMap[K, V]
+	gen   atomic.Uint64
+
+	// uncommitted is the generation of the batch that has been applied, but whose commit has
+	// failed, or 0.
+	uncommitted uint64
+}
+
+// NewReplica returns an empty replica.  If the primary uses Add, the replica must be created
+// WithAdd.
+func NewReplica[K comparable, V any](opts ...Option[K, V]) *Replica[K, V] {
+	// nolint:exhaustivestruct
+	return &Replica[K, V]{lrmap: New(opts...)}
+}
+
+// Generation returns the generation of the primary that the replica has last applied.
+func (r *Replica[K, V]) Generation() uint64 { return r.gen.Load() }
+
+// Apply applies and commits batch.  After the first batch, batches must be applied in the order
+// of their generations without gaps.  If the commit fails, the generation does not advance, and
+// applying the batch again only retries the commit.
+func (r *Replica[K, V]) Apply(batch CommitBatch[K, V]) error {
+	if gen := r.gen.Load(); gen != 0 && batch.Generation != gen+1 {
+		return fmt.Errorf("%w: got generation %d after %d", ErrReplicaGap, batch.Generation, gen)
+	}
+
+	if r.uncommitted != batch.Generation {
+		if err := r.lrmap.ApplyOps(batch.Ops); err != nil {
+			return err
+		}
+
+		r.uncommitted = batch.Generation
+	}
+
+	if err := r.lrmap.TryCommit(); err != nil {
+		return err
+	}
+
+	r.uncommitted = 0
+	r.gen.Store(batch.Generation)
+
+	return nil
+}
+
+// Follow applies the batches of ch until ch is closed, ctx is done, or a batch fails.
+func (r *Replica[K, V]) Follow(ctx context.Context, ch <-chan CommitBatch[K, V]) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case batch, ok := <-ch:
+			if !ok {
+				return nil
+			}
+
+			if err := r.Apply(batch); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (r *Replica[K, V]) NewReadHandler() *ReadHandler[K, V] { return r.lrmap.NewReadHandler() }
@@ -0,0 +1,111 @@
+package lrmap
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestReplica(t *testing.T) {
+	primary := New(WithRedoCompaction[string, int]())
+	replica := NewReplica(WithAdd[string, int]())
+
+	batches, cancel := primary.Subscribe()
+
+	done := make(chan error)
+
+	go func() {
+		done <- replica.Follow(context.Background(), batches)
+	}()
+
+	for i := 0; i < 10; i++ {
+		primary.Set("set", i)
+		Add(primary, "sum", i)
+
+		if i%4 == 0 {
+			primary.Delete("set")
+		}
+
+		primary.Commit()
+
+		// Generation may be read while Follow applies
+		if gen := replica.Generation(); gen > uint64(i+1) {
+			t.Errorf("Generation() = %d while following generation %d", gen, i+1)
+		}
+	}
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("Follow(): %v", err)
+	}
+
+	if gen := replica.Generation(); gen != 10 {
+		t.Errorf("Generation() = %d, want 10", gen)
+	}
+
+	rh := replica.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if rh.Get("set") != 9 || rh.Get("sum") != 45 {
+		t.Errorf("replica has diverged: set = %d, sum = %d", rh.Get("set"), rh.Get("sum"))
+	}
+}
+
+func TestReplicaGap(t *testing.T) {
+	replica := NewReplica[string, int]()
+
+	if err := replica.Apply(CommitBatch[string, int]{Generation: 5}); err != nil {
+		t.Fatalf("Apply(5): %v", err)
+	}
+
+	if err := replica.Apply(CommitBatch[string, int]{Generation: 7}); !errors.Is(err, ErrReplicaGap) {
+		t.Errorf("Apply(7) after 5, want ErrReplicaGap, got %v", err)
+	}
+}
+
+func TestReplicaCommitFails(t *testing.T) {
+	errVeto := errors.New("veto")
+	veto := true
+
+	replica := NewReplica(WithAdd[string, int](), WithPreCommit(func([]Op[string, int]) error {
+		if veto {
+			return errVeto
+		}
+
+		return nil
+	}))
+
+	batch := CommitBatch[string, int]{Generation: 1, Ops: []Op[string, int]{{Kind: OpAdd, Key: "sum", Value: 1}}}
+
+	if err := replica.Apply(batch); !errors.Is(err, errVeto) {
+		t.Fatalf("Apply(1), want veto, got %v", err)
+	}
+
+	if gen := replica.Generation(); gen != 0 {
+		t.Errorf("Generation() after failed commit = %d, want 0", gen)
+	}
+
+	veto = false
+
+	if err := replica.Apply(batch); err != nil {
+		t.Fatalf("Apply(1) again: %v", err)
+	}
+
+	if gen := replica.Generation(); gen != 1 {
+		t.Errorf("Generation() = %d, want 1", gen)
+	}
+
+	rh := replica.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if sum := rh.Get("sum"); sum != 1 {
+		t.Errorf("sum = %d, want 1: the retried batch has been applied twice", sum)
+	}
+}
@@ -0,0 +1,84 @@
+package lrmap
+
+import "math/rand/v2"
+
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// Sample returns min(n, Len()) pseudo-random entries of the live view.  The sample is neither
+// uniform nor reproducible and is meant for auditing and debugging, not for statistics.
+func (rh *ReadHandler[K, V]) Sample(n int) []Entry[K, V] {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	if n <= 0 {
+		return nil
+	}
+
+	if data, ok := rh.inner.live.data.(MapArena[K, V]); ok {
+		return sampleMap(data, n)
+	}
+
+	return sampleReservoir(rh.inner.live.data, n)
+}
+
+// sampleMap relies on the randomized start of map iteration, so that it does not need to visit
+// the whole map.  If the random starts keep hitting sampled keys, it fills up the sample in
+// iteration order.
+func sampleMap[K comparable, V any](data MapArena[K, V], n int) []Entry[K, V] {
+	if n >= len(data) {
+		return sampleReservoir[K, V](data, n)
+	}
+
+	const attemptsPerEntry = 4
+
+	sample := make([]Entry[K, V], 0, n)
+	seen := make(map[K]struct{}, n)
+
+	for attempt := 0; attempt < attemptsPerEntry*n && len(sample) < n; attempt++ {
+		for key, value := range data {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				sample = append(sample, Entry[K, V]{Key: key, Value: value})
+			}
+
+			break
+		}
+	}
+
+	for key, value := range data {
+		if len(sample) == n {
+			break
+		}
+
+		if _, ok := seen[key]; !ok {
+			sample = append(sample, Entry[K, V]{Key: key, Value: value})
+		}
+	}
+
+	return sample
+}
+
+func sampleReservoir[K comparable, V any](data Arena[K, V], n int) []Entry[K, V] {
+	sample := make([]Entry[K, V], 0, min(n, data.Len()))
+	seen := 0
+
+	data.Iterate(func(key K, value V) bool {
+		seen++
+
+		if len(sample) < n {
+			sample = append(sample, Entry[K, V]{Key: key, Value: value})
+		} else if i := rand.IntN(seen); i < n {
+			sample[i] = Entry[K, V]{Key: key, Value: value}
+		}
+
+		return true
+	})
+
+	return sample
+}
@@ -0,0 +1,51 @@
+package lrmap
+
+import (
+	"cmp"
+	"testing"
+)
+
+func TestSample(t *testing.T) {
+	for name, opt := range map[string]Option[int, int]{
+		"map":   WithArena(NewMapArena[int, int]),
+		"btree": WithOrderedArena[int, int](cmp.Compare[int]),
+	} {
+		lrm := New(opt)
+		rh := lrm.NewReadHandler()
+
+		for i := 0; i < 1000; i++ {
+			lrm.Set(i, -i)
+		}
+
+		lrm.Commit()
+
+		rh.Enter()
+
+		sample := rh.Sample(10)
+		if len(sample) != 10 {
+			t.Errorf("%s: Sample(10), want 10 entries, got %d", name, len(sample))
+		}
+
+		seen := make(map[int]bool)
+
+		for _, e := range sample {
+			if e.Value != -e.Key || seen[e.Key] {
+				t.Errorf("%s: Sample(10): bad or duplicate entry %v", name, e)
+			}
+
+			seen[e.Key] = true
+		}
+
+		// the random starts keep hitting sampled keys, so this needs to fill up the sample
+		if most := rh.Sample(999); len(most) != 999 {
+			t.Errorf("%s: Sample(999), want 999 entries, got %d", name, len(most))
+		}
+
+		if all := rh.Sample(2000); len(all) != 1000 {
+			t.Errorf("%s: Sample(2000), want all 1000 entries, got %d", name, len(all))
+		}
+
+		rh.Leave()
+		rh.Close()
+	}
+}
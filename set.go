@@ -0,0 +1,122 @@
+package lrmap
+
+import "iter"
+
+// Set is a left-right set, i.e. an LRMap without values.  Like with LRMap, changes become
+// visible to readers (see SetReader) by Commit only.
+type Set[K comparable] struct {
+	lrmap *LRMap[K, struct{}]
+}
+
+func NewSet[K comparable](opts ...Option[K, struct{}]) *Set[K] {
+	return &Set[K]{lrmap: New(opts...)}
+}
+
+// Add adds keys.  Keys that a validator rejects are dropped.
+func (s *Set[K]) Add(keys ...K) {
+	m := s.lrmap
+
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.writable() != nil {
+		return
+	}
+
+	for _, key := range keys {
+		key = m.normalize(key)
+		if m.validate(key, struct{}{}) == nil {
+			m.set(key, struct{}{})
+		}
+	}
+}
+
+func (s *Set[K]) Remove(keys ...K) {
+	m := s.lrmap
+
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.writable() != nil {
+		return
+	}
+
+	for _, key := range keys {
+		m.delete(m.normalize(key))
+	}
+}
+
+// Contains reports whether key is in the write side of the set.
+func (s *Set[K]) Contains(key K) bool { return s.lrmap.Contains(key) }
+
+// Len returns the size of the write side of the set.
+func (s *Set[K]) Len() int {
+	m := s.lrmap
+
+	m.lock()
+	defer m.mu.Unlock()
+
+	m.syncAll()
+
+	return m.writeMap.Load().data.Len()
+}
+
+// Union adds all keys of seq.
+func (s *Set[K]) Union(seq iter.Seq[K]) {
+	m := s.lrmap
+
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.writable() != nil {
+		return
+	}
+
+	for key := range seq {
+		key = m.normalize(key)
+		if m.validate(key, struct{}{}) == nil {
+			m.set(key, struct{}{})
+		}
+	}
+}
+
+// Intersect removes all keys that are not in seq.
+func (s *Set[K]) Intersect(seq iter.Seq[K]) {
+	keep := make(map[K]struct{})
+	for key := range seq {
+		keep[s.lrmap.normalize(key)] = struct{}{}
+	}
+
+	s.lrmap.DeleteFunc(func(key K, _ struct{}) bool {
+		_, ok := keep[key]
+
+		return !ok
+	})
+}
+
+func (s *Set[K]) Commit() { s.lrmap.Commit() }
+
+func (s *Set[K]) NewReader() *SetReader[K] {
+	return &SetReader[K]{rh: s.lrmap.NewReadHandler()}
+}
+
+// SetReader is the read handler of a Set, with the same rules as ReadHandler.
+type SetReader[K comparable] struct {
+	rh *ReadHandler[K, struct{}]
+}
+
+func (r *SetReader[K]) Enter()   { r.rh.Enter() }
+func (r *SetReader[K]) Leave()   { r.rh.Leave() }
+func (r *SetReader[K]) Close()   { r.rh.Close() }
+func (r *SetReader[K]) Recycle() { r.rh.Recycle() }
+
+func (r *SetReader[K]) Contains(key K) bool { return r.rh.Contains(key) }
+func (r *SetReader[K]) Len() int            { return r.rh.Len() }
+
+// All returns an iterator over the live view.  The reader must be entered while the iterator is
+// in use.
+func (r *SetReader[K]) All() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		r.rh.Iterate(func(key K, _ struct{}) bool { return yield(key) })
+	}
+}
@@ -0,0 +1,53 @@
+package lrmap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSet(t *testing.T) {
+	s := NewSet[int]()
+	s.Add(1, 2, 3, 4)
+	s.Remove(4)
+
+	r := s.NewReader()
+	defer r.Close()
+
+	r.Enter()
+	if r.Contains(1) {
+		t.Errorf("uncommitted key is visible")
+	}
+	r.Leave()
+
+	s.Commit()
+
+	other := NewSet[int]()
+	other.Add(2, 3, 5)
+	other.Commit()
+
+	or := other.NewReader()
+	defer or.Close()
+
+	or.Enter()
+	s.Intersect(or.All())
+	s.Union(or.All())
+	or.Leave()
+
+	if n := s.Len(); n != 3 {
+		t.Errorf("Len() = %d, want 3", n)
+	}
+
+	s.Commit()
+
+	r.Enter()
+	defer r.Leave()
+
+	got := slices.Sorted(r.All())
+	if want := []int{2, 3, 5}; !slices.Equal(got, want) {
+		t.Errorf("All() = %v, want %v", got, want)
+	}
+
+	if !r.Contains(5) || r.Contains(1) {
+		t.Errorf("Contains disagrees with All()")
+	}
+}
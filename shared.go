@@ -0,0 +1,137 @@
+package lrmap
+
+import (
+	"fmt"
+	"runtime"
+	"sync/atomic"
+)
+
+// SharedReadHandler is a read handler that may be used by many goroutines at once.  All
+// goroutines that are entered at the same time share one view of the map: the first one to
+// enter pins the live arena, the last one to leave releases it.
+//
+// To keep commits from waiting forever on overlapping readers, goroutines that enter while a
+// commit waits for the handler are held back until the other goroutines have left.  Hence,
+// unlike with ReadHandler, Enter is not wait-free.
+type SharedReadHandler[K comparable, V any] struct {
+	lrmap   *LRMap[K, V]
+	slot    *epochSlot
+	live    atomic.Pointer[side[K, V]]
+	cleanup cleanup
+
+	// refs counts the entered goroutines.  It is -1 while a goroutine pins or releases the
+	// view, and -2 once the handler has been closed.
+	refs atomic.Int64
+}
+
+const (
+	sharedBusy   = -1
+	sharedClosed = -2
+)
+
+func (m *LRMap[K, V]) NewSharedReadHandler() *SharedReadHandler[K, V] {
+	// nolint:exhaustivestruct
+	s := &SharedReadHandler[K, V]{lrmap: m, slot: m.readHandlers.acquire()}
+	s.slot.owner.Store(&ReaderInfo{ID: m.handlerIDs.Add(1)}) // nolint:exhaustivestruct
+
+	s.cleanup = addCleanup(s, m.readHandlers.release, s.slot)
+
+	return s
+}
+
+func (s *SharedReadHandler[K, V]) Enter() {
+	for {
+		switch n := s.refs.Load(); {
+		case n == sharedClosed:
+			panic("reader illegal state: must not use after Close()")
+		case n == sharedBusy:
+			runtime.Gosched()
+		case n == 0:
+			if s.refs.CompareAndSwap(0, sharedBusy) {
+				s.slot.epoch.Add(1)
+
+				if s.lrmap.closed.Load() {
+					s.slot.epoch.Add(1)
+					s.refs.Store(0)
+
+					panic(fmt.Errorf("reader illegal state: %w", ErrClosed))
+				}
+
+				s.live.Store(s.lrmap.readMap.Load())
+				s.refs.Store(1)
+
+				return
+			}
+		case s.live.Load() != s.lrmap.readMap.Load():
+			// A commit has published a new arena and waits for this handler to leave the
+			// old one, so do not join the old view, but wait for the others to drain.
+			runtime.Gosched()
+		default:
+			if s.refs.CompareAndSwap(n, n+1) {
+				return
+			}
+		}
+	}
+}
+
+func (s *SharedReadHandler[K, V]) Leave() {
+	for {
+		switch n := s.refs.Load(); {
+		case n == sharedBusy:
+			runtime.Gosched()
+		case n <= 0:
+			panic("reader illegal state: must not Leave() more often than Enter()")
+		case n == 1:
+			if s.refs.CompareAndSwap(1, sharedBusy) {
+				s.live.Store(nil)
+				s.slot.epoch.Add(1)
+				s.refs.Store(0)
+
+				return
+			}
+		default:
+			if s.refs.CompareAndSwap(n, n-1) {
+				return
+			}
+		}
+	}
+}
+
+func (s *SharedReadHandler[K, V]) Get(key K) V {
+	value, _ := s.GetOK(key)
+
+	return value
+}
+
+func (s *SharedReadHandler[K, V]) GetOK(key K) (V, bool) {
+	return s.view().data.Get(s.lrmap.normalize(key))
+}
+
+func (s *SharedReadHandler[K, V]) Contains(key K) bool {
+	return s.view().data.Contains(s.lrmap.normalize(key))
+}
+
+func (s *SharedReadHandler[K, V]) Len() int { return s.view().data.Len() }
+
+func (s *SharedReadHandler[K, V]) Iterate(fn func(_ K, _ V) bool) { s.view().data.Iterate(fn) }
+
+// Close releases the handler.  No goroutine may be entered.
+func (s *SharedReadHandler[K, V]) Close() {
+	if !s.refs.CompareAndSwap(0, sharedClosed) {
+		panic("reader illegal state: must Leave() before Close()")
+	}
+
+	stopCleanup(s, s.cleanup)
+	s.lrmap.readHandlers.release(s.slot)
+}
+
+// view returns the pinned arena.  The calling goroutine must be entered, which cannot be
+// checked beyond some goroutine being entered.
+func (s *SharedReadHandler[K, V]) view() *side[K, V] {
+	live := s.live.Load()
+	if live == nil || s.refs.Load() <= 0 {
+		panic("reader illegal state: must Enter() before operating on data")
+	}
+
+	return live
+}
@@ -0,0 +1,60 @@
+package lrmap
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestSharedReadHandler(t *testing.T) {
+	lrm := New[int, int]()
+
+	shared := lrm.NewSharedReadHandler()
+	defer shared.Close()
+
+	var (
+		wg   sync.WaitGroup
+		stop = make(chan struct{})
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+
+				shared.Enter()
+
+				// all keys are written in the same commit, so a view has either none or all
+				if n, v := shared.Len(), shared.Get(1); n != 0 && v != n {
+					t.Errorf("inconsistent view: Len() = %d, Get(1) = %d", n, v)
+				}
+
+				shared.Leave()
+			}
+		}()
+	}
+
+	for gen := 1; gen <= 100; gen++ {
+		for k := 0; k < gen; k++ {
+			lrm.Set(k, gen)
+		}
+
+		lrm.Commit()
+	}
+
+	close(stop)
+	wg.Wait()
+
+	shared.Enter()
+	if v := shared.Get(99); v != 100 {
+		t.Errorf("Get(99), want 100, got %d", v)
+	}
+	shared.Leave()
+}
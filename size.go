@@ -0,0 +1,71 @@
+package lrmap
+
+import "unsafe"
+
+// WithSizer registers a function that reports the memory referenced by an entry beyond its
+// inline size, e.g. the backing arrays of strings and slices.  SizeEstimate adds it up.
+func WithSizer[K comparable, V any](sizer func(K, V) uint64) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.sizer = sizer
+	}
+}
+
+// SizeEstimate returns an approximation of the bytes used by both arenas and the redo log.
+//
+// The estimate is based on the inline sizes of keys and values and heuristics on the
+// overhead of the arena implementation.  Memory referenced by keys and values is only
+// accounted for by a sizer (see WithSizer), and only once, as both arenas share it.
+func (m *LRMap[K, V]) SizeEstimate() uint64 {
+	m.lock()
+	defer m.mu.Unlock()
+
+	m.syncAll()
+
+	size := arenaSize(m.left.data) + arenaSize(m.right.data)
+
+	var op operation[K, V]
+
+	size += uint64(cap(m.redoLog)) * uint64(unsafe.Sizeof(op))
+
+	if m.sizer != nil {
+		m.writeMap.Load().data.Iterate(func(key K, value V) bool {
+			size += m.sizer(key, value)
+
+			return true
+		})
+	}
+
+	return size
+}
+
+func arenaSize[K comparable, V any](a Arena[K, V]) uint64 {
+	switch a := a.(type) {
+	case *swissArena[K, V]:
+		var slot swissSlot[K, V]
+
+		return uint64(len(a.ctrl))*8 + uint64(len(a.slots))*uint64(unsafe.Sizeof(slot))
+	case *btree[K, V]:
+		var (
+			item btreeItem[K, V]
+			node btreeNode[K, V]
+		)
+
+		// Nodes are between half full and full, so assume three quarters.  Inner nodes hold
+		// a child pointer per item.
+		slots := uint64(a.length) * 4 / 3
+		nodes := slots/btreeMaxItems + 1
+
+		return slots*uint64(unsafe.Sizeof(item)+unsafe.Sizeof(&node)) + nodes*uint64(unsafe.Sizeof(node))
+	default:
+		var (
+			key   K
+			value V
+		)
+
+		// Go maps grow at a load factor of 7/8 to half of that, so assume two thirds of the
+		// slots are in use, each with one control byte.
+		entry := uint64(unsafe.Sizeof(key) + unsafe.Sizeof(value) + 1)
+
+		return uint64(a.Len()) * entry * 3 / 2
+	}
+}
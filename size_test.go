@@ -0,0 +1,39 @@
+package lrmap
+
+import "testing"
+
+func TestSizeEstimate(t *testing.T) {
+	lrm := New[int, int]()
+
+	empty := lrm.SizeEstimate()
+
+	for i := 0; i < 1000; i++ {
+		lrm.Set(i, i)
+	}
+
+	pending := lrm.SizeEstimate()
+	if pending <= empty {
+		t.Errorf("SizeEstimate() did not grow with pending writes: %d <= %d", pending, empty)
+	}
+
+	lrm.Commit()
+
+	// both arenas hold 1000 entries of at least 16 bytes each
+	if committed := lrm.SizeEstimate(); committed < 2*1000*16 {
+		t.Errorf("SizeEstimate() after Commit, want at least %d, got %d", 2*1000*16, committed)
+	}
+}
+
+func TestSizeEstimateSizer(t *testing.T) {
+	lrm := New(WithSizer(func(k string, v []byte) uint64 { return uint64(len(k) + cap(v)) }))
+	without := New[string, []byte]()
+
+	for _, m := range []*LRMap[string, []byte]{lrm, without} {
+		m.Set("key", make([]byte, 1<<20))
+		m.Commit()
+	}
+
+	if diff := lrm.SizeEstimate() - without.SizeEstimate(); diff != 1<<20+3 {
+		t.Errorf("sizer contribution, want %d, got %d", 1<<20+3, diff)
+	}
+}
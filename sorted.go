@@ -0,0 +1,82 @@
+package lrmap
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+	"strings"
+)
+
+// IterateSorted is like ReadHandler.Iterate, but visits the keys in ascending order.
+//
+// The sorted key order is computed at most once per committed arena and shared by all read
+// handlers, since the read view cannot change until the writer takes the arena back.
+func IterateSorted[K cmp.Ordered, V any](rh *ReadHandler[K, V], fn func(K, V) bool) {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must call Enter() before iterating")
+	}
+
+	live := rh.inner.live
+	fn = rh.inner.counted(fn)
+
+	for _, key := range sortedKeys(live) {
+		value, _ := live.data.Get(key)
+		if ok := fn(key, value); !ok {
+			return
+		}
+	}
+}
+
+// AllSorted returns an iterator over the live view of rh in ascending key order.  The handler
+// must be entered while the iterator is in use.
+func AllSorted[K cmp.Ordered, V any](rh *ReadHandler[K, V]) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		IterateSorted(rh, yield)
+	}
+}
+
+// IteratePrefix calls fn for all entries of the live view whose keys start with prefix, in
+// ascending key order, until fn returns false.  It finds the keys by binary search in the
+// sorted key order of the arena (see IterateSorted), so after the first call per generation, it
+// does not visit the entire map.
+func IteratePrefix[K ~string, V any](rh *ReadHandler[K, V], prefix string, fn func(K, V) bool) {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must call Enter() before iterating")
+	}
+
+	live := rh.inner.live
+	keys := sortedKeys(live)
+	fn = rh.inner.counted(fn)
+
+	i, _ := slices.BinarySearch(keys, K(prefix))
+
+	for _, key := range keys[i:] {
+		if !strings.HasPrefix(string(key), prefix) {
+			return
+		}
+
+		value, _ := live.data.Get(key)
+		if ok := fn(key, value); !ok {
+			return
+		}
+	}
+}
+
+func sortedKeys[K cmp.Ordered, V any](s *side[K, V]) []K {
+	if keys := s.sorted.Load(); keys != nil {
+		return *keys
+	}
+
+	keys := appendKeys(make([]K, 0, s.data.Len()), s.data)
+	slices.Sort(keys)
+
+	// Concurrent readers may race to fill the cache; they all computed the same order, so it
+	// does not matter whose result wins.
+	s.sorted.Store(&keys)
+
+	return keys
+}
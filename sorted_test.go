@@ -0,0 +1,105 @@
+package lrmap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIterateSorted(t *testing.T) {
+	lrm := New[int, int]()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	for _, k := range []int{5, 3, 9, 1, 7} {
+		lrm.Set(k, k*10)
+	}
+
+	lrm.Commit()
+
+	rh.Enter()
+
+	var keys []int
+
+	IterateSorted(rh, func(k int, v int) bool {
+		if v != k*10 {
+			t.Errorf("IterateSorted: key %d, want value %d, got %d", k, k*10, v)
+		}
+
+		keys = append(keys, k)
+
+		return true
+	})
+
+	if want := []int{1, 3, 5, 7, 9}; !slices.Equal(keys, want) {
+		t.Errorf("IterateSorted: want keys %v, got %v", want, keys)
+	}
+
+	rh.Leave()
+
+	lrm.Delete(3)
+	lrm.Set(4, 40)
+	lrm.Commit()
+
+	// commit twice, so that the arena with the stale cache is live again
+	lrm.Commit()
+
+	rh.Enter()
+
+	keys = keys[:0]
+
+	for k := range AllSorted(rh) {
+		keys = append(keys, k)
+		if k == 5 {
+			break
+		}
+	}
+
+	if want := []int{1, 4, 5}; !slices.Equal(keys, want) {
+		t.Errorf("AllSorted: want keys %v, got %v", want, keys)
+	}
+
+	rh.Leave()
+}
+
+func TestIteratePrefix(t *testing.T) {
+	type tenantKey string
+
+	lrm := New[tenantKey, int]()
+
+	for i, k := range []tenantKey{"b/2", "a/1", "b/1", "ba", "c/1", "b/"} {
+		lrm.Set(k, i)
+	}
+
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	var keys []tenantKey
+
+	IteratePrefix(rh, "b/", func(k tenantKey, _ int) bool {
+		keys = append(keys, k)
+
+		return true
+	})
+
+	if want := []tenantKey{"b/", "b/1", "b/2"}; !slices.Equal(keys, want) {
+		t.Errorf("IteratePrefix(b/): want keys %v, got %v", want, keys)
+	}
+
+	n := 0
+
+	IteratePrefix(rh, "", func(tenantKey, int) bool {
+		n++
+
+		return n < 2
+	})
+
+	if n != 2 {
+		t.Errorf("IteratePrefix() has not stopped when fn returned false")
+	}
+}
@@ -0,0 +1,120 @@
+package lrmap
+
+import "unsafe"
+
+type Stats struct {
+	// Generation is the number of commits so far.
+	Generation uint64
+
+	// Len is the number of entries in the write map, CommittedLen the number of entries
+	// readers see.
+	Len          int
+	CommittedLen int
+
+	// HighWaterLen is the most entries readers have seen at any commit.  Arenas that Commit
+	// rebuilds (see WithRebuildFactor) are pre-sized for it.
+	HighWaterLen int
+
+	// PendingOps is the number of operations in the redo log, that is, the operations the next
+	// commit has to replay on the other arena.  PendingKeys is the number of distinct keys
+	// they touch.
+	PendingOps  int
+	PendingKeys int
+
+	// StaleEntries is the number of committed entries that pending operations have overwritten
+	// or deleted.  The committed arena keeps their values alive until the next commit.
+	// SupersededOps is the number of pending sets that a later operation on the same
+	// key has made obsolete; the redo log keeps their values alive until the next commit.
+	StaleEntries  int
+	SupersededOps int
+
+	// ActiveReaders is the number of read handlers that are currently entered.
+	ActiveReaders int
+
+	// AbandonedReaders is the number of entered handlers that commits have stopped waiting
+	// for, AbandonedTotal the number of handlers abandoned so far (see WithReaderWatchdog).
+	AbandonedReaders int
+	AbandonedTotal   uint64
+
+	// StaleBytes estimates the memory retained by stale entries and superseded operations.
+	// It includes the memory reported by the sizer, if any (see WithSizer).
+	StaleBytes uint64
+}
+
+func (m *LRMap[K, V]) Stats() Stats {
+	m.lock()
+	defer m.mu.Unlock()
+
+	m.syncAll()
+
+	committed := m.readMap.Load().data
+
+	s := Stats{
+		Generation:     m.generation,
+		Len:            m.writeMap.Load().data.Len(),
+		CommittedLen:   committed.Len(),
+		HighWaterLen:   m.highWater,
+		PendingOps:     len(m.redoLog),
+		AbandonedTotal: m.watchdog.abandoned,
+	}
+
+	var value V
+
+	valueSize := uint64(unsafe.Sizeof(value))
+
+	// The last operation of each key is the live one, so walk the redo log backwards.
+	seen := make(map[K]struct{}, len(m.redoLog))
+
+	for i := len(m.redoLog) - 1; i >= 0; i-- {
+		op := m.redoLog[i]
+
+		// prefix deletions are not attributed to the keys they delete
+		if op.typ == OpDeletePrefix {
+			continue
+		}
+
+		if _, ok := seen[op.key]; ok {
+			if op.typ == OpSet {
+				s.SupersededOps++
+				s.StaleBytes += valueSize + m.size(op.key, op.value)
+			}
+
+			continue
+		}
+
+		seen[op.key] = struct{}{}
+
+		if old, ok := committed.Get(op.key); ok {
+			s.StaleEntries++
+			s.StaleBytes += valueSize + m.size(op.key, old)
+		}
+	}
+
+	s.PendingKeys = len(seen)
+
+	m.readHandlers.forEach(func(slot *epochSlot) {
+		if epoch := slot.epoch.Load(); epoch%2 == 1 {
+			s.ActiveReaders++
+
+			if slot.abandoned.Load() == epoch {
+				s.AbandonedReaders++
+			}
+		}
+	})
+
+	return s
+}
+
+func (m *LRMap[K, V]) size(key K, value V) uint64 {
+	if m.sizer == nil {
+		return 0
+	}
+
+	return m.sizer(key, value)
+}
+
+// ApproxLen returns the number of entries as of the latest commit.  Unlike Stats and
+// ReadHandler.Len, it takes neither the writer lock nor a read handler, so it may be called
+// from any goroutine at any rate, e.g. by health checks.  It may lag behind readers that have
+// entered the very latest generation.
+func (m *LRMap[K, V]) ApproxLen() int { return int(m.committedLen.Load()) }
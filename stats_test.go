@@ -0,0 +1,74 @@
+package lrmap
+
+import (
+	"testing"
+	"unsafe"
+)
+
+func TestStatsStale(t *testing.T) {
+	lrm := New(WithSizer(func(_ int, v []byte) uint64 { return uint64(cap(v)) }))
+
+	lrm.Set(1, make([]byte, 100))
+	lrm.Set(2, make([]byte, 100))
+	lrm.Commit()
+
+	lrm.Set(1, make([]byte, 10)) // superseded
+	lrm.Set(1, make([]byte, 10))
+	lrm.Delete(2)
+	lrm.Set(3, make([]byte, 10))
+
+	var v []byte
+
+	want := Stats{
+		Generation:    1,
+		Len:           2,
+		CommittedLen:  2,
+		HighWaterLen:  2,
+		PendingOps:    4,
+		PendingKeys:   3,
+		StaleEntries:  2,
+		SupersededOps: 1,
+		StaleBytes:    3*uint64(unsafe.Sizeof(v)) + 100 + 100 + 10,
+	}
+
+	if s := lrm.Stats(); s != want {
+		t.Errorf("Stats(),\nwant %+v,\ngot  %+v", want, s)
+	}
+
+	lrm.Commit()
+
+	if s := lrm.Stats(); s.PendingOps != 0 || s.StaleEntries != 0 || s.StaleBytes != 0 {
+		t.Errorf("Stats() after Commit, want nothing pending or stale, got %+v", s)
+	}
+}
+
+func TestApproxLen(t *testing.T) {
+	lrm := New[int, int]()
+
+	for i := 0; i < 10; i++ {
+		lrm.Set(i, i)
+	}
+
+	if n := lrm.ApproxLen(); n != 0 {
+		t.Errorf("ApproxLen() before the first commit, want 0, got %d", n)
+	}
+
+	lrm.Commit()
+	lrm.Delete(0)
+
+	if n := lrm.ApproxLen(); n != 10 {
+		t.Errorf("ApproxLen() after the commit, want 10, got %d", n)
+	}
+
+	lrm.Commit()
+
+	if n := lrm.ApproxLen(); n != 9 {
+		t.Errorf("ApproxLen() after deleting, want 9, got %d", n)
+	}
+
+	_ = lrm.Close()
+
+	if n := lrm.ApproxLen(); n != 0 {
+		t.Errorf("ApproxLen() after Close(), want 0, got %d", n)
+	}
+}
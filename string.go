@@ -0,0 +1,78 @@
+package lrmap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// stringMaxEntries caps the number of entries String prints, so that dumping a huge map
+// stays cheap and readable.
+const stringMaxEntries = 10
+
+// String returns a short description of the map's state and its first few entries (in
+// iteration order) of the write map.
+func (m *LRMap[K, V]) String() string {
+	m.lock()
+	defer m.mu.Unlock()
+
+	m.syncAll()
+
+	var b strings.Builder
+
+	data := m.writeMap.Load().data
+
+	fmt.Fprintf(&b, "LRMap{generation: %d, committed: %d, len: %d, pending: %d, entries: ",
+		m.generation, m.readMap.Load().data.Len(), data.Len(), len(m.redoLog))
+	writeEntries(&b, data)
+	b.WriteString("}")
+
+	return b.String()
+}
+
+// String returns a short description of the live view and its first few entries.  It does
+// not panic on handlers that are not ready or not entered.
+func (rh *ReadHandler[K, V]) String() string {
+	switch {
+	case rh.inner == nil || !rh.ready:
+		return "ReadHandler{not ready}"
+	case !rh.inner.entered():
+		return "ReadHandler{not entered}"
+	}
+
+	var b strings.Builder
+
+	live := rh.inner.live
+
+	fmt.Fprintf(&b, "ReadHandler{generation: %d, len: %d, entries: ", live.gen, live.data.Len())
+	writeEntries(&b, live.data)
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func writeEntries[K comparable, V any](b *strings.Builder, data Arena[K, V]) {
+	b.WriteString("[")
+
+	n := 0
+
+	data.Iterate(func(key K, value V) bool {
+		if n == stringMaxEntries {
+			return false
+		}
+
+		if n > 0 {
+			b.WriteString(" ")
+		}
+
+		fmt.Fprintf(b, "%v:%v", key, value)
+		n++
+
+		return true
+	})
+
+	if more := data.Len() - n; more > 0 {
+		fmt.Fprintf(b, " ... (%d more)", more)
+	}
+
+	b.WriteString("]")
+}
@@ -0,0 +1,39 @@
+package lrmap
+
+import (
+	"cmp"
+	"testing"
+)
+
+func TestString(t *testing.T) {
+	lrm := New(WithOrderedArena[int, string](cmp.Compare[int]))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	if s, want := rh.String(), "ReadHandler{not entered}"; s != want {
+		t.Errorf("String(), want %q, got %q", want, s)
+	}
+
+	for i := 0; i < 12; i++ {
+		lrm.Set(i, "v")
+	}
+
+	lrm.Commit()
+	lrm.Set(12, "w")
+
+	want := "LRMap{generation: 1, committed: 12, len: 13, pending: 1, entries: " +
+		"[0:v 1:v 2:v 3:v 4:v 5:v 6:v 7:v 8:v 9:v ... (3 more)]}"
+	if s := lrm.String(); s != want {
+		t.Errorf("String(),\nwant %q,\ngot  %q", want, s)
+	}
+
+	rh.Enter()
+
+	want = "ReadHandler{generation: 1, len: 12, entries: [0:v 1:v 2:v 3:v 4:v 5:v 6:v 7:v 8:v 9:v ... (2 more)]}"
+	if s := rh.String(); s != want {
+		t.Errorf("String(),\nwant %q,\ngot  %q", want, s)
+	}
+
+	rh.Leave()
+}
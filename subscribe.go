@@ -0,0 +1,110 @@
+package lrmap
+
+import "sync"
+
+// subscriptionBuffer is the number of batches a subscriber may lag behind before commits
+// block on it.
+const subscriptionBuffer = 16
+
+type (
+	// CommitBatch is the list of operations a commit has published, in order.  With
+	// WithRedoCompaction, each key has at most one (merged) operation.
+	CommitBatch[K comparable, V any] struct {
+		Generation uint64
+		Ops        []Op[K, V]
+	}
+
+	// delivery is the batch of the last commit and the subscribers it has to be sent to.
+	delivery[K comparable, V any] struct {
+		batch CommitBatch[K, V]
+		subs  []*subscription[K, V]
+	}
+
+	subscription[K comparable, V any] struct {
+		ch     chan CommitBatch[K, V]
+		done   chan struct{}
+		cancel sync.Once
+	}
+)
+
+// Subscribe returns a channel that receives a CommitBatch for every following commit, in the
+// order of generations, e.g. to replicate the map.  Batches are delivered after the writer lock
+// has been released, but a commit blocks until all subscribers have room for its batch, so
+// subscribers must keep receiving until they call cancel.  cancel closes the channel.
+func (m *LRMap[K, V]) Subscribe() (<-chan CommitBatch[K, V], func()) {
+	sub := &subscription[K, V]{
+		ch:   make(chan CommitBatch[K, V], subscriptionBuffer),
+		done: make(chan struct{}),
+	}
+
+	m.lock()
+
+	if m.subs == nil {
+		m.subs = make(map[*subscription[K, V]]struct{})
+	}
+
+	m.subs[sub] = struct{}{}
+
+	m.mu.Unlock()
+
+	return sub.ch, func() { m.unsubscribe(sub) }
+}
+
+// unsubscribe closes the channel of sub.  It must not be called with m.mu held.
+func (m *LRMap[K, V]) unsubscribe(sub *subscription[K, V]) {
+	sub.cancel.Do(func() {
+		m.lock()
+		delete(m.subs, sub)
+		m.mu.Unlock()
+
+		close(sub.done)
+
+		// wait for a delivery in progress, which may still send on the channel
+		m.deliverMu.Lock()
+		close(sub.ch)
+		m.deliverMu.Unlock()
+	})
+}
+
+// prepareDelivery captures the batch of the current commit for all subscribers.  The caller
+// must hold m.mu.
+func (m *LRMap[K, V]) prepareDelivery(ops []Op[K, V]) {
+	if len(m.subs) == 0 {
+		return
+	}
+
+	if ops == nil {
+		ops = m.pendingOps()
+	}
+
+	m.delivery.batch = CommitBatch[K, V]{Generation: m.generation, Ops: ops}
+	m.delivery.subs = m.delivery.subs[:0]
+
+	for sub := range m.subs {
+		m.delivery.subs = append(m.delivery.subs, sub)
+	}
+}
+
+// deliver sends the captured batch.  The caller must hold m.mu, which deliver releases once it
+// has taken over the delivery, so that batches are delivered in order.
+func (m *LRMap[K, V]) deliver() {
+	if len(m.delivery.subs) == 0 {
+		m.mu.Unlock()
+
+		return
+	}
+
+	m.deliverMu.Lock()
+	defer m.deliverMu.Unlock()
+
+	batch, subs := m.delivery.batch, m.delivery.subs
+	m.delivery.subs = nil
+	m.mu.Unlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.ch <- batch:
+		case <-sub.done:
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package lrmap
+
+import "testing"
+
+func TestSubscribe(t *testing.T) {
+	lrm := New(WithRedoCompaction[string, int]())
+
+	batches, cancel := lrm.Subscribe()
+
+	lrm.Set("a", 1)
+	lrm.Set("a", 2)
+	lrm.Set("b", 1)
+	lrm.Commit()
+
+	lrm.Delete("b")
+	lrm.Commit()
+
+	first, second := <-batches, <-batches
+
+	if first.Generation != 1 || len(first.Ops) != 2 || first.Ops[0] != (Op[string, int]{Kind: OpSet, Key: "a", Value: 2}) {
+		t.Errorf("first batch = %+v", first)
+	}
+
+	if second.Generation != 2 || len(second.Ops) != 1 || second.Ops[0].Kind != OpDelete {
+		t.Errorf("second batch = %+v", second)
+	}
+
+	cancel()
+	cancel()
+
+	if _, ok := <-batches; ok {
+		t.Errorf("channel is open after cancel")
+	}
+
+	// commits must not block on a cancelled subscription
+	for i := 0; i < 2*subscriptionBuffer; i++ {
+		lrm.Commit()
+	}
+}
+
+func TestSubscribeBlockedCancel(t *testing.T) {
+	lrm := New[int, int]()
+
+	_, cancel := lrm.Subscribe()
+
+	for i := 0; i < subscriptionBuffer; i++ {
+		lrm.Commit()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		lrm.Commit()
+	}()
+
+	cancel()
+	<-done
+}
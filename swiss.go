@@ -0,0 +1,230 @@
+package lrmap
+
+import "math/bits"
+
+// The swiss table keeps one control byte per slot, grouped into words of eight slots.  A
+// control byte is either swissEmpty, swissDeleted, or the lower seven bits of the hash (h2)
+// of the key in that slot.  Lookups compare h2 against a whole group at once and touch the
+// slots only on (likely) matches.
+const (
+	swissGroupSize = 8
+
+	swissEmpty   = 0x80
+	swissDeleted = 0xfe
+
+	swissLSB = 0x0101010101010101
+	swissMSB = 0x8080808080808080
+
+	// swissEmptyGroup is a control word with all slots empty.
+	swissEmptyGroup = swissEmpty * swissLSB
+)
+
+type (
+	// swissArena is an open-addressing hash table in the style of Abseil's swiss tables.  It
+	// stores keys and values inline in a single slice, which keeps memory overhead low and
+	// makes iteration cache friendly.
+	swissArena[K comparable, V any] struct {
+		hash   func(K) uint64
+		ctrl   []uint64
+		slots  []swissSlot[K, V]
+		length int
+		dead   int
+	}
+
+	swissSlot[K comparable, V any] struct {
+		key   K
+		value V
+	}
+)
+
+// WithSwissArena makes both sides of the map use a swiss table instead of a Go map.  If hash is
+// nil, a seeded hash of the comparable key is used (requires Go 1.24).
+func WithSwissArena[K comparable, V any](hash func(K) uint64) Option[K, V] {
+	if hash == nil {
+		hash = defaultHasher[K]()
+	}
+
+	return WithArena(func() Arena[K, V] { return newSwissArena[K, V](hash) })
+}
+
+func newSwissArena[K comparable, V any](hash func(K) uint64) *swissArena[K, V] {
+	// nolint:exhaustivestruct
+	return &swissArena[K, V]{hash: hash}
+}
+
+func (a *swissArena[K, V]) Get(key K) (V, bool) {
+	if i, ok := a.find(key, a.hash(key)); ok {
+		return a.slots[i].value, true
+	}
+
+	var zero V
+
+	return zero, false
+}
+
+func (a *swissArena[K, V]) Contains(key K) bool {
+	_, ok := a.find(key, a.hash(key))
+
+	return ok
+}
+
+func (a *swissArena[K, V]) Set(key K, value V) {
+	h := a.hash(key)
+
+	if i, ok := a.find(key, h); ok {
+		a.slots[i].value = value
+
+		return
+	}
+
+	if a.length+a.dead >= a.capacity()*7/8 {
+		a.rehash()
+	}
+
+	a.insert(key, value, h)
+}
+
+func (a *swissArena[K, V]) Delete(key K) {
+	i, ok := a.find(key, a.hash(key))
+	if !ok {
+		return
+	}
+
+	// If the group still has an empty slot, no probe sequence ever continued past this group,
+	// so the slot may become empty again instead of leaving a tombstone.
+	group := i / swissGroupSize
+	if swissMatchEmpty(a.ctrl[group]) != 0 {
+		a.setCtrl(i, swissEmpty)
+	} else {
+		a.setCtrl(i, swissDeleted)
+		a.dead++
+	}
+
+	a.slots[i] = swissSlot[K, V]{} // nolint:exhaustivestruct
+	a.length--
+}
+
+func (a *swissArena[K, V]) Len() int { return a.length }
+
+func (a *swissArena[K, V]) Iterate(fn func(K, V) bool) {
+	for group, ctrl := range a.ctrl {
+		for full := ^ctrl & swissMSB; full != 0; full &= full - 1 {
+			slot := &a.slots[group*swissGroupSize+bits.TrailingZeros64(full)/8]
+			if ok := fn(slot.key, slot.value); !ok {
+				return
+			}
+		}
+	}
+}
+
+func (a *swissArena[K, V]) Clone() Arena[K, V] {
+	return &swissArena[K, V]{
+		hash:   a.hash,
+		ctrl:   append([]uint64(nil), a.ctrl...),
+		slots:  append([]swissSlot[K, V](nil), a.slots...),
+		length: a.length,
+		dead:   a.dead,
+	}
+}
+
+func (a *swissArena[K, V]) capacity() int { return len(a.slots) }
+
+// find returns the slot index of key.
+func (a *swissArena[K, V]) find(key K, h uint64) (int, bool) {
+	if len(a.ctrl) == 0 {
+		return 0, false
+	}
+
+	mask := uint64(len(a.ctrl) - 1)
+	h2 := h & 0x7f
+
+	for group, step := (h>>7)&mask, uint64(1); ; group, step = (group+step)&mask, step+1 {
+		ctrl := a.ctrl[group]
+
+		for match := swissMatchH2(ctrl, h2); match != 0; match &= match - 1 {
+			i := int(group)*swissGroupSize + bits.TrailingZeros64(match)/8
+			if a.slots[i].key == key {
+				return i, true
+			}
+		}
+
+		if swissMatchEmpty(ctrl) != 0 {
+			return 0, false
+		}
+	}
+}
+
+// insert stores a key that is known to be absent into the first free slot of its probe
+// sequence.  The table must have room for it.
+func (a *swissArena[K, V]) insert(key K, value V, h uint64) {
+	mask := uint64(len(a.ctrl) - 1)
+
+	for group, step := (h>>7)&mask, uint64(1); ; group, step = (group+step)&mask, step+1 {
+		if free := a.ctrl[group] & swissMSB; free != 0 {
+			i := int(group)*swissGroupSize + bits.TrailingZeros64(free)/8
+
+			if a.ctrlAt(i) == swissDeleted {
+				a.dead--
+			}
+
+			a.setCtrl(i, uint8(h&0x7f))
+			a.slots[i] = swissSlot[K, V]{key: key, value: value}
+			a.length++
+
+			return
+		}
+	}
+}
+
+// rehash moves all entries into a fresh table, which drops all tombstones and doubles the
+// capacity if the table is more than half full.
+func (a *swissArena[K, V]) rehash() {
+	groups := len(a.ctrl)
+	if groups == 0 {
+		groups = 1
+	} else if a.length >= a.capacity()/2 {
+		groups *= 2
+	}
+
+	ctrl, slots := a.ctrl, a.slots
+
+	a.ctrl = make([]uint64, groups)
+	for i := range a.ctrl {
+		a.ctrl[i] = swissEmptyGroup
+	}
+
+	a.slots = make([]swissSlot[K, V], groups*swissGroupSize)
+	a.length = 0
+	a.dead = 0
+
+	for group, c := range ctrl {
+		for full := ^c & swissMSB; full != 0; full &= full - 1 {
+			slot := &slots[group*swissGroupSize+bits.TrailingZeros64(full)/8]
+			a.insert(slot.key, slot.value, a.hash(slot.key))
+		}
+	}
+}
+
+func (a *swissArena[K, V]) ctrlAt(i int) uint8 {
+	return uint8(a.ctrl[i/swissGroupSize] >> (8 * (i % swissGroupSize)))
+}
+
+func (a *swissArena[K, V]) setCtrl(i int, c uint8) {
+	shift := 8 * (i % swissGroupSize)
+	word := &a.ctrl[i/swissGroupSize]
+	*word = *word&^(0xff<<shift) | uint64(c)<<shift
+}
+
+// swissMatchH2 returns a mask with the high bit set in each byte of ctrl that equals h2.  It
+// may report false positives, which are weeded out by comparing the keys.
+func swissMatchH2(ctrl, h2 uint64) uint64 {
+	x := ctrl ^ (swissLSB * h2)
+
+	return (x - swissLSB) &^ x & swissMSB
+}
+
+// swissMatchEmpty returns a mask with the high bit set in each byte of ctrl that is empty (but
+// not deleted).
+func swissMatchEmpty(ctrl uint64) uint64 {
+	return ctrl &^ (ctrl << 6) & swissMSB
+}
@@ -0,0 +1,81 @@
+package lrmap
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSwissArenaRandom(t *testing.T) {
+	rnd := rand.New(rand.NewSource(1))
+	arena := newSwissArena[int, int](defaultHasher[int]())
+	ref := make(map[int]int)
+
+	for i := 0; i < 50000; i++ {
+		k := rnd.Intn(3000)
+
+		if rnd.Intn(3) == 0 {
+			arena.Delete(k)
+			delete(ref, k)
+		} else {
+			arena.Set(k, i)
+			ref[k] = i
+		}
+	}
+
+	if arena.Len() != len(ref) {
+		t.Fatalf("Len(), want %d, got %d", len(ref), arena.Len())
+	}
+
+	for k := 0; k < 3000; k++ {
+		v, ok := ref[k]
+		if _v, _ok := arena.Get(k); _ok != ok || _v != v {
+			t.Errorf("Get(%d), want (%d, %t), got (%d, %t)", k, v, ok, _v, _ok)
+		}
+	}
+
+	n := 0
+
+	arena.Clone().Iterate(func(k int, v int) bool {
+		n++
+
+		if ref[k] != v {
+			t.Errorf("Iterate: key %d, want value %d, got %d", k, ref[k], v)
+		}
+
+		return true
+	})
+
+	if n != len(ref) {
+		t.Errorf("Iterate: want %d entries, got %d", len(ref), n)
+	}
+}
+
+func TestWithSwissArena(t *testing.T) {
+	// a poor hash function provokes collisions and long probe sequences
+	lrm := New(WithSwissArena[string, int](func(k string) uint64 { return uint64(len(k)) }))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set("a", 1)
+	lrm.Set("b", 2)
+	lrm.Set("cc", 3)
+	lrm.Delete("a")
+	lrm.Commit()
+
+	rh.Enter()
+
+	if v, ok := rh.GetOK("b"); !ok || v != 2 {
+		t.Errorf("GetOK(b), want (2, true), got (%d, %t)", v, ok)
+	}
+
+	if _, ok := rh.GetOK("a"); ok {
+		t.Errorf("GetOK(a) after Delete, want false")
+	}
+
+	if n := rh.Len(); n != 2 {
+		t.Errorf("Len(), want 2, got %d", n)
+	}
+
+	rh.Leave()
+}
@@ -0,0 +1,145 @@
+package lrmap
+
+import "sync/atomic"
+
+// SyncMapAdapter offers the methods of sync.Map on top of an LRMap, so that code written
+// against sync.Map can be migrated (and benchmarked) by swapping the type.  Loads read the
+// committed view, writes are published according to the commit policy of the adapter.
+type SyncMapAdapter[K comparable, V any] struct {
+	lrmap       *LRMap[K, V]
+	commitEvery int64
+	pending     atomic.Int64
+}
+
+// NewSyncMapAdapter returns an adapter that commits after every commitEvery writes.  With a
+// commitEvery of 1, a Load sees every preceding write, as with sync.Map, but every write pays
+// for a commit.  With 0, the adapter never commits by itself, see Commit.
+func NewSyncMapAdapter[K comparable, V any](commitEvery int, opts ...Option[K, V]) *SyncMapAdapter[K, V] {
+	// nolint:exhaustivestruct
+	return &SyncMapAdapter[K, V]{lrmap: New(opts...), commitEvery: int64(commitEvery)}
+}
+
+// LRMap returns the underlying map.
+func (a *SyncMapAdapter[K, V]) LRMap() *LRMap[K, V] { return a.lrmap }
+
+// Commit publishes all writes since the last commit.
+func (a *SyncMapAdapter[K, V]) Commit() {
+	a.pending.Store(0)
+	a.lrmap.Commit()
+}
+
+func (a *SyncMapAdapter[K, V]) Load(key K) (V, bool) {
+	rh := a.lrmap.NewReadHandler()
+	defer rh.Recycle()
+
+	if rh.TryEnter() != nil {
+		var zero V
+
+		return zero, false
+	}
+
+	defer rh.Leave()
+
+	return rh.GetOK(key)
+}
+
+func (a *SyncMapAdapter[K, V]) Store(key K, value V) {
+	a.lrmap.Set(key, value)
+	a.wrote()
+}
+
+// LoadOrStore returns the existing value for key, including unpublished ones.  Otherwise, it
+// stores value and returns the stored value.  If the entry is rejected, LoadOrStore returns
+// the zero value; use TryLoadOrStore to learn about it.
+func (a *SyncMapAdapter[K, V]) LoadOrStore(key K, value V) (V, bool) {
+	actual, loaded, _ := a.TryLoadOrStore(key, value)
+
+	return actual, loaded
+}
+
+// TryLoadOrStore is like LoadOrStore, but returns the error if the entry is rejected.
+func (a *SyncMapAdapter[K, V]) TryLoadOrStore(key K, value V) (V, bool, error) {
+	m := a.lrmap
+
+	m.throttle()
+	m.lock()
+
+	key = m.normalize(key)
+	m.syncKey(key)
+
+	if actual, ok := m.writeMap.Load().data.Get(key); ok {
+		m.mu.Unlock()
+
+		return actual, true, nil
+	}
+
+	err := m.writable()
+	if err == nil {
+		err = m.admitSet(key, value)
+	}
+
+	if err != nil {
+		m.mu.Unlock()
+
+		var zero V
+
+		return zero, false, err
+	}
+
+	// a reducer may have changed the value
+	actual, _ := m.writeMap.Load().data.Get(key)
+
+	m.mu.Unlock()
+
+	a.wrote()
+
+	return actual, false, nil
+}
+
+func (a *SyncMapAdapter[K, V]) LoadAndDelete(key K) (V, bool) {
+	value, ok := a.lrmap.Pop(key)
+	if ok {
+		a.wrote()
+	}
+
+	return value, ok
+}
+
+func (a *SyncMapAdapter[K, V]) Delete(key K) {
+	a.lrmap.Delete(key)
+	a.wrote()
+}
+
+// Range calls fn for the entries of the committed view until fn returns false.  The entries are
+// copied before fn is called, so that fn may write to the adapter (which may commit).
+func (a *SyncMapAdapter[K, V]) Range(fn func(key K, value V) bool) {
+	rh := a.lrmap.NewReadHandler()
+	if rh.TryEnter() != nil {
+		rh.Recycle()
+
+		return
+	}
+
+	entries := make([]Entry[K, V], 0, rh.Len())
+
+	rh.Iterate(func(key K, value V) bool {
+		entries = append(entries, Entry[K, V]{Key: key, Value: value})
+
+		return true
+	})
+
+	rh.Leave()
+	rh.Recycle()
+
+	for _, e := range entries {
+		if !fn(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+func (a *SyncMapAdapter[K, V]) wrote() {
+	if a.commitEvery > 0 && a.pending.Add(1) >= a.commitEvery {
+		a.Commit()
+	}
+}
@@ -0,0 +1,92 @@
+package lrmap
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSyncMapAdapter(t *testing.T) {
+	a := NewSyncMapAdapter[string, int](1)
+
+	a.Store("a", 1)
+
+	if v, ok := a.Load("a"); !ok || v != 1 {
+		t.Errorf("Load(a) = %d, %t, want 1, true", v, ok)
+	}
+
+	if v, loaded := a.LoadOrStore("a", 2); !loaded || v != 1 {
+		t.Errorf("LoadOrStore(a) = %d, %t, want 1, true", v, loaded)
+	}
+
+	if v, loaded := a.LoadOrStore("b", 2); loaded || v != 2 {
+		t.Errorf("LoadOrStore(b) = %d, %t, want 2, false", v, loaded)
+	}
+
+	// writing while ranging must not deadlock with the commit
+	n := 0
+	a.Range(func(key string, value int) bool {
+		a.Store(key+key, value)
+		n++
+
+		return true
+	})
+
+	if n != 2 {
+		t.Errorf("Range visited %d entries, want 2", n)
+	}
+
+	if v, ok := a.LoadAndDelete("aa"); !ok || v != 1 {
+		t.Errorf("LoadAndDelete(aa) = %d, %t, want 1, true", v, ok)
+	}
+
+	a.Delete("bb")
+
+	if _, ok := a.Load("bb"); ok {
+		t.Errorf("Load(bb) after Delete succeeded")
+	}
+}
+
+func TestSyncMapAdapterBatched(t *testing.T) {
+	a := NewSyncMapAdapter[int, int](3)
+
+	a.Store(1, 1)
+	a.Store(2, 2)
+
+	if _, ok := a.Load(1); ok {
+		t.Errorf("Load(1) succeeded before the batch was full")
+	}
+
+	a.Store(3, 3)
+
+	if _, ok := a.Load(1); !ok {
+		t.Errorf("Load(1) failed after the batch was full")
+	}
+}
+
+func TestSyncMapAdapterLoadOrStoreRejected(t *testing.T) {
+	errNegative := errors.New("negative")
+
+	a := NewSyncMapAdapter(1, WithValidator(func(_ string, value int) error {
+		if value < 0 {
+			return errNegative
+		}
+
+		return nil
+	}))
+
+	if v, loaded, err := a.TryLoadOrStore("a", -1); !errors.Is(err, errNegative) || loaded || v != 0 {
+		t.Errorf("TryLoadOrStore(a, -1) = %d, %t, %v, want 0, false, errNegative", v, loaded, err)
+	}
+
+	if _, ok := a.Load("a"); ok {
+		t.Error("rejected entry has been stored")
+	}
+
+	if err := a.LRMap().Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if v, loaded, err := a.TryLoadOrStore("b", 1); !errors.Is(err, ErrClosed) || loaded || v != 0 {
+		t.Errorf("TryLoadOrStore(b) after Close = %d, %t, %v, want 0, false, ErrClosed", v, loaded, err)
+	}
+}
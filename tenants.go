@@ -0,0 +1,269 @@
+package lrmap
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+type (
+	// Registry manages many maps by name, e.g. one per tenant.  Maps are created on first use
+	// and share the background goroutines of the registry, so a registry of thousands of maps
+	// runs just two goroutines.
+	Registry[K comparable, V any] struct {
+		opts RegistryOptions[K, V]
+
+		mu     sync.Mutex
+		maps   map[string]*tenant[K, V]
+		closed bool
+
+		stop chan struct{}
+		done sync.WaitGroup
+	}
+
+	// RegistryOptions control a Registry, see NewRegistry.
+	RegistryOptions[K comparable, V any] struct {
+		// CommitInterval makes the registry commit every map with pending writes at that
+		// interval.  Zero leaves committing to the user.
+		CommitInterval time.Duration
+
+		// IdleTimeout makes the registry close and remove maps that have not been obtained
+		// by Map for that long.  Since closing discards pending writes, an idle map with
+		// pending writes is committed first and only removed once it has none.  Zero keeps
+		// maps until they are removed.
+		IdleTimeout time.Duration
+
+		// Options, if set, returns the options of the map named name.
+		Options func(name string) []Option[K, V]
+
+		// Clock, if set, replaces the system clock for the intervals and timeouts of the
+		// registry.  It is not passed on to the maps; see WithClock.
+		Clock Clock
+	}
+
+	tenant[K comparable, V any] struct {
+		m *LRMap[K, V]
+
+		// lastUsed is the time of the last call of Map in Unix nanoseconds.
+		lastUsed int64
+	}
+)
+
+// NewRegistry returns a registry that creates maps with the given options.  The registry must
+// be closed to stop its goroutines.
+func NewRegistry[K comparable, V any](opts RegistryOptions[K, V]) *Registry[K, V] {
+	// nolint:exhaustivestruct
+	r := &Registry[K, V]{
+		opts: opts,
+		maps: make(map[string]*tenant[K, V]),
+		stop: make(chan struct{}),
+	}
+
+	if r.opts.Clock == nil {
+		r.opts.Clock = systemClock{}
+	}
+
+	if opts.CommitInterval > 0 {
+		r.done.Add(1)
+
+		go r.every(opts.CommitInterval, r.commitPending)
+	}
+
+	if opts.IdleTimeout > 0 {
+		r.done.Add(1)
+
+		go r.every(opts.IdleTimeout/2, r.sweep)
+	}
+
+	return r
+}
+
+// Map returns the map named name, creating it if it does not exist.  It returns nil after the
+// registry has been closed.
+func (r *Registry[K, V]) Map(name string) *LRMap[K, V] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	t, ok := r.maps[name]
+	if !ok {
+		var opts []Option[K, V]
+		if r.opts.Options != nil {
+			opts = r.opts.Options(name)
+		}
+
+		t = &tenant[K, V]{m: New(opts...)} // nolint:exhaustivestruct
+		r.maps[name] = t
+	}
+
+	t.lastUsed = r.opts.Clock.Now().UnixNano()
+
+	return t.m
+}
+
+// Lookup returns the map named name, if it exists.  Unlike Map, it does not count as a use.
+func (r *Registry[K, V]) Lookup(name string) (*LRMap[K, V], bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	t, ok := r.maps[name]
+	if !ok {
+		return nil, false
+	}
+
+	return t.m, true
+}
+
+// Remove closes and removes the map named name.  It returns the error of closing the map, or
+// false if there is no such map.
+func (r *Registry[K, V]) Remove(name string) (bool, error) {
+	r.mu.Lock()
+	t, ok := r.maps[name]
+	delete(r.maps, name)
+	r.mu.Unlock()
+
+	if !ok {
+		return false, nil
+	}
+
+	return true, t.m.Close()
+}
+
+// Names returns the names of all maps in ascending order.
+func (r *Registry[K, V]) Names() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	names := make([]string, 0, len(r.maps))
+	for name := range r.maps {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Stats returns the sum of the Stats of all maps.  Generation is the total number of commits.
+func (r *Registry[K, V]) Stats() Stats {
+	var sum Stats
+
+	for _, m := range r.all() {
+		s := m.Stats()
+		sum.Generation += s.Generation
+		sum.Len += s.Len
+		sum.CommittedLen += s.CommittedLen
+		sum.PendingOps += s.PendingOps
+		sum.PendingKeys += s.PendingKeys
+		sum.StaleEntries += s.StaleEntries
+		sum.SupersededOps += s.SupersededOps
+		sum.ActiveReaders += s.ActiveReaders
+		sum.AbandonedReaders += s.AbandonedReaders
+		sum.AbandonedTotal += s.AbandonedTotal
+		sum.StaleBytes += s.StaleBytes
+	}
+
+	return sum
+}
+
+// Close stops the goroutines of the registry and closes all maps, which discards their pending
+// writes.  It returns the errors of closing the maps.
+func (r *Registry[K, V]) Close() error {
+	r.mu.Lock()
+
+	if r.closed {
+		r.mu.Unlock()
+
+		return ErrClosed
+	}
+
+	r.closed = true
+	maps := r.maps
+	r.maps = make(map[string]*tenant[K, V])
+
+	r.mu.Unlock()
+
+	close(r.stop)
+	r.done.Wait()
+
+	var errs []error
+
+	for _, t := range maps {
+		if err := t.m.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Registry[K, V]) all() []*LRMap[K, V] {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	maps := make([]*LRMap[K, V], 0, len(r.maps))
+	for _, t := range r.maps {
+		maps = append(maps, t.m)
+	}
+
+	return maps
+}
+
+func (r *Registry[K, V]) every(interval time.Duration, fn func()) {
+	defer r.done.Done()
+
+	every(r.opts.Clock, interval, r.stop, fn)
+}
+
+// commitPending commits all maps that have pending writes.
+func (r *Registry[K, V]) commitPending() {
+	for _, m := range r.all() {
+		if m.pending() {
+			_ = m.TryCommit()
+		}
+	}
+}
+
+// sweep closes and removes the maps that have been idle for longer than IdleTimeout.  It
+// commits idle maps with pending writes instead, so that a later sweep can remove them; a map
+// whose commit fails is kept.
+func (r *Registry[K, V]) sweep() {
+	deadline := r.opts.Clock.Now().Add(-r.opts.IdleTimeout).UnixNano()
+
+	var idle, pending []*LRMap[K, V]
+
+	r.mu.Lock()
+
+	for name, t := range r.maps {
+		switch {
+		case t.lastUsed >= deadline:
+		case t.m.pending():
+			pending = append(pending, t.m)
+		default:
+			delete(r.maps, name)
+			idle = append(idle, t.m)
+		}
+	}
+
+	r.mu.Unlock()
+
+	for _, m := range pending {
+		_ = m.TryCommit()
+	}
+
+	for _, m := range idle {
+		_ = m.Close()
+	}
+}
+
+// pending tells whether m has writes that have not been committed.
+func (m *LRMap[K, V]) pending() bool {
+	m.lock()
+	defer m.mu.Unlock()
+
+	return len(m.redoLog) > 0 || m.async.Load() != nil
+}
@@ -0,0 +1,189 @@
+package lrmap
+
+import (
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRegistryMaps(t *testing.T) {
+	created := make(map[string]int)
+
+	r := NewRegistry(RegistryOptions[string, int]{
+		Options: func(name string) []Option[string, int] {
+			created[name]++
+
+			return []Option[string, int]{WithKeyNormalizer[string, int](func(k string) string { return name + "/" + k })}
+		},
+	})
+
+	a := r.Map("a")
+	a.Set("x", 1)
+	a.Commit()
+
+	if r.Map("a") != a || created["a"] != 1 {
+		t.Errorf("Map() has created the map again")
+	}
+
+	b := r.Map("b")
+	b.Set("x", 2)
+	b.Set("y", 3)
+
+	if names := r.Names(); !slices.Equal(names, []string{"a", "b"}) {
+		t.Errorf("Names(), want [a b], got %v", names)
+	}
+
+	if _, ok := r.Lookup("c"); ok {
+		t.Errorf("Lookup() of a missing map, want false")
+	}
+
+	s := r.Stats()
+	if s.Generation != 1 || s.Len != 3 || s.CommittedLen != 1 || s.PendingOps != 2 {
+		t.Errorf("Stats(), got %+v", s)
+	}
+
+	if ok, err := r.Remove("b"); !ok || err != nil {
+		t.Errorf("Remove(b), want (true, nil), got (%t, %v)", ok, err)
+	}
+
+	if err := b.TryCommit(); !errors.Is(err, ErrClosed) {
+		t.Errorf("removed map: TryCommit(), want ErrClosed, got %v", err)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Errorf("Close(): %v", err)
+	}
+
+	if err := a.TryCommit(); !errors.Is(err, ErrClosed) {
+		t.Errorf("after Close(): TryCommit(), want ErrClosed, got %v", err)
+	}
+
+	if r.Map("a") != nil {
+		t.Errorf("Map() after Close(), want nil")
+	}
+}
+
+func TestRegistryMapsBackground(t *testing.T) {
+	r := NewRegistry(RegistryOptions[int, int]{
+		CommitInterval: time.Millisecond,
+		IdleTimeout:    50 * time.Millisecond,
+		Options:        nil,
+		Clock:          nil,
+	})
+	defer r.Close()
+
+	m := r.Map("m")
+	m.Set(1, 1)
+
+	for m.ApproxLen() != 1 {
+		time.Sleep(time.Millisecond)
+	}
+
+	for {
+		if _, ok := r.Lookup("m"); !ok {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+
+	if err := m.TryCommit(); !errors.Is(err, ErrClosed) {
+		t.Errorf("idle map: TryCommit(), want ErrClosed, got %v", err)
+	}
+}
+
+func TestRegistryMapsClock(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)} // nolint:exhaustivestruct
+	r := NewRegistry(RegistryOptions[int, int]{
+		CommitInterval: 0,
+		IdleTimeout:    10 * time.Second,
+		Options:        nil,
+		Clock:          clock,
+	})
+	defer r.Close()
+
+	_ = r.Map("m")
+
+	// the sweeper runs every 5s of virtual time; the map is idle for longer than 10s only
+	// after the third run
+	for i := 1; i <= 3; i++ {
+		for clock.Timers() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		if _, ok := r.Lookup("m"); !ok {
+			t.Fatalf("map removed after %ds", 5*(i-1))
+		}
+
+		clock.Advance(5 * time.Second)
+	}
+
+	for {
+		if _, ok := r.Lookup("m"); !ok {
+			break
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRegistrySweepKeepsPendingWrites(t *testing.T) {
+	errVeto := errors.New("veto")
+	clock := &fakeClock{now: time.Unix(0, 0)} // nolint:exhaustivestruct
+
+	var veto atomic.Bool
+
+	veto.Store(true)
+
+	r := NewRegistry(RegistryOptions[int, int]{
+		CommitInterval: 0,
+		IdleTimeout:    10 * time.Second,
+		Options: func(string) []Option[int, int] {
+			return []Option[int, int]{WithPreCommit(func([]Op[int, int]) error {
+				if veto.Load() {
+					return errVeto
+				}
+
+				return nil
+			})}
+		},
+		Clock: clock,
+	})
+	defer r.Close()
+
+	m := r.Map("m")
+	m.Set(1, 1)
+
+	// let the background sweep run once, then sweep by hand
+	for clock.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	clock.Advance(time.Minute)
+
+	for clock.Timers() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	r.sweep()
+
+	// the commit has been vetoed, so closing the map would lose the write
+	if _, ok := r.Lookup("m"); !ok || m.Get(1) != 1 {
+		t.Fatal("idle map with pending writes has been removed")
+	}
+
+	veto.Store(false)
+	r.sweep()
+
+	if _, ok := r.Lookup("m"); !ok || m.ApproxLen() != 1 {
+		t.Fatal("idle map with pending writes has not been committed")
+	}
+
+	r.sweep()
+
+	if _, ok := r.Lookup("m"); ok {
+		t.Error("idle map without pending writes has not been removed")
+	}
+}
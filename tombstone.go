@@ -0,0 +1,87 @@
+package lrmap
+
+import "time"
+
+// WithTombstones makes deletions observable for a retention window, e.g. for replicating them
+// downstream: a deleted entry is gone from Get and Iterate, but its metadata stays behind as a
+// tombstone, which ReadHandler.GetMeta and ReadHandler.Tombstones report with Deleted set.
+// Purge removes tombstones.  WithTombstones implies WithEntryMeta.
+func WithTombstones[K comparable, V any]() Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.entryMeta = true
+		m.tombstones = true
+	}
+}
+
+// Purge removes the tombstones of entries that have been deleted at least olderThan ago and
+// returns how many it removed.  Readers see them gone after the next commit, which records
+// the removals as OpPurge operations.  Purge requires WithTombstones.
+func (m *LRMap[K, V]) Purge(olderThan time.Duration) int {
+	if !m.tombstones {
+		panic("illegal use: Purge() requires WithTombstones()")
+	}
+
+	m.lock()
+	defer m.mu.Unlock()
+
+	if m.writable() != nil {
+		return 0
+	}
+
+	m.syncAll()
+
+	cutoff := m.clock.Now().Add(-olderThan)
+
+	var keys []K
+
+	for key, meta := range m.writeMap.Load().meta {
+		if !meta.Deleted.IsZero() && !meta.Deleted.After(cutoff) {
+			keys = append(keys, key)
+		}
+	}
+
+	for _, key := range keys {
+		m.purge(key)
+	}
+
+	return len(keys)
+}
+
+// purge removes the tombstone of key from the write map.  The caller must hold m.mu.
+func (m *LRMap[K, V]) purge(key K) {
+	m.syncKey(key)
+
+	meta := m.writeMap.Load().meta
+	if entry, ok := meta[key]; !ok || entry.Deleted.IsZero() {
+		return
+	}
+
+	delete(meta, key)
+
+	// nolint:exhaustivestruct
+	m.log(operation[K, V]{typ: OpPurge, key: key})
+}
+
+// Tombstones calls fn with the metadata of all tombstones in the live view until fn returns
+// false.  It requires WithTombstones.
+func (rh *ReadHandler[K, V]) Tombstones(fn func(key K, meta EntryMeta) bool) {
+	rh.assertReady()
+
+	if !rh.inner.entered() {
+		panic("reader illegal state: must call Enter() before iterating")
+	}
+
+	if !rh.inner.lrmap.tombstones {
+		panic("illegal use: Tombstones() requires WithTombstones()")
+	}
+
+	for key, meta := range rh.inner.live.meta {
+		if meta.Deleted.IsZero() {
+			continue
+		}
+
+		if ok := fn(key, meta); !ok {
+			return
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package lrmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTombstones(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts []Option[string, int]
+	}{
+		{name: "default"},
+		{name: "compaction", opts: []Option[string, int]{WithRedoCompaction[string, int]()}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			clock := &fakeClock{now: time.Unix(100, 0)} // nolint:exhaustivestruct
+			lrm := New(append(tc.opts, WithTombstones[string, int](), WithClock[string, int](clock))...)
+
+			rh := lrm.NewReadHandler()
+			defer rh.Close()
+
+			lrm.Set("a", 1)
+			lrm.Set("b", 2)
+			lrm.Commit()
+
+			clock.now = time.Unix(200, 0)
+
+			lrm.Delete("a")
+			lrm.Delete("a")
+			lrm.Delete("missing")
+			lrm.Commit()
+
+			// both sides must agree, so check after the replaying commit, too
+			for i := 0; i < 2; i++ {
+				rh.Enter()
+
+				if rh.Contains("a") {
+					t.Error("Contains(a) after Delete")
+				}
+
+				if meta, ok := rh.GetMeta("a"); !ok || meta.Deleted != time.Unix(200, 0) || meta.Generation != 2 {
+					t.Errorf("GetMeta(a), want tombstone of generation 2, got %+v, %t", meta, ok)
+				}
+
+				if _, ok := rh.GetMeta("missing"); ok {
+					t.Error("tombstone of a key that has never existed")
+				}
+
+				if got := tombstones(rh); len(got) != 1 || got[0] != "a" {
+					t.Errorf("Tombstones(), want [a], got %v", got)
+				}
+
+				rh.Leave()
+				lrm.Commit()
+			}
+
+			clock.now = time.Unix(300, 0)
+
+			if n := lrm.Purge(time.Hour); n != 0 {
+				t.Errorf("Purge(1h) of a tombstone of 100s, want 0, got %d", n)
+			}
+
+			if n := lrm.Purge(100 * time.Second); n != 1 {
+				t.Errorf("Purge(100s) of a tombstone of 100s, want 1, got %d", n)
+			}
+
+			rh.Enter()
+
+			if got := tombstones(rh); len(got) != 1 {
+				t.Errorf("Tombstones() before the commit, want [a], got %v", got)
+			}
+
+			rh.Leave()
+
+			for i := 0; i < 2; i++ {
+				lrm.Commit()
+				rh.Enter()
+
+				if got := tombstones(rh); len(got) != 0 {
+					t.Errorf("Tombstones() after the commit, want none, got %v", got)
+				}
+
+				rh.Leave()
+			}
+
+			// deleting and purging within one commit
+			lrm.Delete("b")
+			lrm.Purge(0)
+			lrm.Commit()
+			lrm.Commit()
+
+			rh.Enter()
+			defer rh.Leave()
+
+			if _, ok := rh.GetMeta("b"); ok || rh.Contains("b") {
+				t.Error("b has survived Delete and Purge")
+			}
+		})
+	}
+}
+
+func TestTombstoneRecreated(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(100, 0)} // nolint:exhaustivestruct
+	lrm := New(WithTombstones[string, int](), WithClock[string, int](clock))
+
+	lrm.Set("a", 1)
+	lrm.Delete("a")
+
+	clock.now = time.Unix(200, 0)
+
+	lrm.Set("a", 2)
+	lrm.Commit()
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	want := EntryMeta{Created: time.Unix(200, 0), Updated: time.Unix(200, 0), Generation: 1} // nolint:exhaustivestruct
+	if got, ok := rh.GetMeta("a"); !ok || got != want {
+		t.Errorf("GetMeta(a), want %+v, got %+v, %t", want, got, ok)
+	}
+}
+
+func tombstones(rh *ReadHandler[string, int]) []string {
+	var keys []string
+
+	rh.Tombstones(func(key string, _ EntryMeta) bool {
+		keys = append(keys, key)
+
+		return true
+	})
+
+	return keys
+}
+
+func TestTombstonePurgeThenAdd(t *testing.T) {
+	lrm := New(WithRedoCompaction[string, int](), WithTombstones[string, int](), WithAdd[string, int]())
+
+	lrm.Set("k", 10)
+	lrm.Commit()
+	lrm.Delete("k")
+	lrm.Purge(0)
+	Add(lrm, "k", 5)
+	lrm.Commit()
+	lrm.Commit()
+
+	if v := lrm.Get("k"); v != 5 {
+		t.Errorf("writer Get(k), want 5, got %d", v)
+	}
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+	defer rh.Leave()
+
+	if v := rh.Get("k"); v != 5 {
+		t.Errorf("reader Get(k), want 5, got %d", v)
+	}
+}
@@ -0,0 +1,114 @@
+package lrmap
+
+import "context"
+
+type (
+	// Tracer starts spans for the phases of a commit.  Its shape follows OpenTelemetry's
+	// trace.Tracer, so an adapter is a few lines:
+	//
+	//	type otelTracer struct{ trace.Tracer }
+	//
+	//	func (t otelTracer) Start(ctx context.Context, name string) (context.Context, lrmap.Span) {
+	//		ctx, span := t.Tracer.Start(ctx, name)
+	//		return ctx, otelSpan{span}
+	//	}
+	Tracer interface {
+		Start(ctx context.Context, name string) (context.Context, Span)
+	}
+
+	// Span is a span started by a Tracer.
+	Span interface {
+		SetAttribute(key string, value int64)
+		End()
+	}
+
+	nopSpan struct{}
+)
+
+// Names of the spans that a commit starts.  The phase spans are children of the commit span.
+const (
+	// SpanCommit covers the entire commit.
+	SpanCommit = "lrmap.commit"
+
+	// SpanSwap covers publishing the write map to readers.
+	SpanSwap = "lrmap.swap"
+
+	// SpanReaderWait covers waiting for the readers of the former read map.  The attribute
+	// AttrStragglers tells how many readers the commit had to wait for.
+	SpanReaderWait = "lrmap.reader_wait"
+
+	// SpanReplay covers syncing the former read map.  The attribute AttrOps tells the length
+	// of the redo log.
+	SpanReplay = "lrmap.replay"
+)
+
+// Attributes of the phase spans.
+const (
+	AttrStragglers = "lrmap.stragglers"
+	AttrOps        = "lrmap.ops"
+)
+
+// WithTracer makes commits report their phases as spans to t, so that commit latency shows up
+// in the traces of the write requests that commit.  Use CommitContext to pass the context of
+// the request; other commits start root spans.  t is called with the writer lock held and must
+// not call into the map.
+func WithTracer[K comparable, V any](t Tracer) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.tracer = t
+	}
+}
+
+// CommitContext is like TryCommit, but starts the commit span as a child of the span in ctx
+// (see WithTracer).  ctx does not cancel the commit.
+func (m *LRMap[K, V]) CommitContext(ctx context.Context) error {
+	m.lock()
+
+	span := m.startCommitSpan(ctx)
+	defer span.End()
+
+	err := m.commit(m.pipeline != nil)
+	m.traceCtx = nil
+
+	if err != nil {
+		m.mu.Unlock()
+
+		return err
+	}
+
+	m.completeCommit()
+
+	return nil
+}
+
+// startCommitSpan starts the commit span and makes it the parent of the phase spans.  The
+// caller must hold m.mu.
+func (m *LRMap[K, V]) startCommitSpan(ctx context.Context) Span {
+	if m.tracer == nil {
+		return nopSpan{}
+	}
+
+	var span Span
+
+	m.traceCtx, span = m.tracer.Start(ctx, SpanCommit)
+
+	return span
+}
+
+// startSpan starts a phase span.  The caller must hold m.mu.
+func (m *LRMap[K, V]) startSpan(name string) Span {
+	if m.tracer == nil {
+		return nopSpan{}
+	}
+
+	ctx := m.traceCtx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	_, span := m.tracer.Start(ctx, name)
+
+	return span
+}
+
+func (nopSpan) SetAttribute(string, int64) {}
+func (nopSpan) End()                       {}
@@ -0,0 +1,115 @@
+package lrmap
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+)
+
+type (
+	testTracer struct {
+		mu    sync.Mutex
+		spans []*testSpan
+	}
+
+	testSpan struct {
+		name   string
+		parent string
+		attrs  map[string]int64
+		ended  bool
+	}
+
+	testSpanKey struct{}
+)
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	parent, _ := ctx.Value(testSpanKey{}).(string)
+	span := &testSpan{name: name, parent: parent, attrs: make(map[string]int64), ended: false}
+	t.spans = append(t.spans, span)
+
+	return context.WithValue(ctx, testSpanKey{}, name), span
+}
+
+func (s *testSpan) SetAttribute(key string, value int64) { s.attrs[key] = value }
+func (s *testSpan) End()                                 { s.ended = true }
+
+func TestTracer(t *testing.T) {
+	tracer := new(testTracer)
+	blocked := make(chan int, 1)
+	lrm := New(
+		WithTracer[int, int](tracer),
+		WithReaderBarrier[int, int](func(stragglers int) {
+			if stragglers > 0 {
+				blocked <- stragglers
+			}
+		}),
+	)
+
+	lrm.Set(1, 1)
+	lrm.Set(2, 2)
+	lrm.Delete(1)
+
+	ctx := context.WithValue(context.Background(), testSpanKey{}, "request")
+	if err := lrm.CommitContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+
+	for _, span := range tracer.spans {
+		names = append(names, span.name)
+
+		if !span.ended {
+			t.Errorf("span %s has not ended", span.name)
+		}
+
+		want := SpanCommit
+		if span.name == SpanCommit {
+			want = "request"
+		}
+
+		if span.parent != want {
+			t.Errorf("parent of span %s, want %s, got %s", span.name, want, span.parent)
+		}
+	}
+
+	if want := []string{SpanCommit, SpanSwap, SpanReaderWait, SpanReplay}; !slices.Equal(names, want) {
+		t.Fatalf("spans, want %v, got %v", want, names)
+	}
+
+	if ops := tracer.spans[3].attrs[AttrOps]; ops != 3 {
+		t.Errorf("%s of the replay span, want 3, got %d", AttrOps, ops)
+	}
+
+	// a commit without context starts a root span
+	tracer.spans = nil
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	lrm.Set(3, 3)
+	rh.Enter()
+
+	done := make(chan struct{})
+
+	go func() {
+		lrm.Commit()
+		close(done)
+	}()
+
+	<-blocked
+	rh.Leave()
+	<-done
+
+	if tracer.spans[0].parent != "" {
+		t.Errorf("parent of the commit span of Commit(), want none, got %s", tracer.spans[0].parent)
+	}
+
+	if n := tracer.spans[2].attrs[AttrStragglers]; n != 1 {
+		t.Errorf("%s of the reader wait span, want 1, got %d", AttrStragglers, n)
+	}
+}
@@ -0,0 +1,63 @@
+package lrmap
+
+import "time"
+
+// ReaderInfo identifies a read handler in reports.  IDs are unique per map; labels are set by
+// the user (see ReadHandler.SetLabel).
+type ReaderInfo struct {
+	ID    uint64
+	Label string
+}
+
+// Straggler is a read handler that was entered when a commit swapped the arenas, so that the
+// commit had to wait for it.  Wait is the time until the commit noticed it had left.
+type Straggler struct {
+	ReaderInfo
+	Wait time.Duration
+}
+
+// WaitReport describes how long a commit waited for readers, and for which.
+type WaitReport struct {
+	Generation uint64
+	Wait       time.Duration
+	Stragglers []Straggler
+}
+
+// LastWait reports on the reader wait of the most recent commit.
+func (m *LRMap[K, V]) LastWait() WaitReport {
+	m.lock()
+	defer m.mu.Unlock()
+
+	return m.lastWait
+}
+
+func (rh *ReadHandler[K, V]) ID() uint64 {
+	rh.assertReady()
+
+	return rh.inner.slot.owner.Load().ID
+}
+
+func (rh *ReadHandler[K, V]) Label() string {
+	rh.assertReady()
+
+	return rh.inner.slot.owner.Load().Label
+}
+
+// SetLabel attaches a label to the handler that shows up in wait reports, to tell which part
+// of a program holds up commits.
+func (rh *ReadHandler[K, V]) SetLabel(label string) {
+	rh.assertReady()
+
+	owner := rh.inner.slot.owner.Load()
+	rh.inner.slot.owner.Store(&ReaderInfo{ID: owner.ID, Label: label})
+}
+
+// WithReaderBarrier registers a function that each commit calls with the number of readers it
+// has to wait for, right before it starts waiting.  It is meant for tests that need to know
+// that a commit is blocked on readers before they let those readers go.  fn is called with the
+// writer lock held and must not call into the map.
+func WithReaderBarrier[K comparable, V any](fn func(stragglers int)) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.readerBarrier = fn
+	}
+}
@@ -0,0 +1,86 @@
+package lrmap
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLastWait(t *testing.T) {
+	lrm := New[int, int]()
+
+	idle := lrm.NewReadHandler()
+	defer idle.Close()
+
+	slow := lrm.NewReadHandler()
+	defer slow.Close()
+
+	slow.SetLabel("slow")
+	slow.Enter()
+
+	if n := lrm.Stats().ActiveReaders; n != 1 {
+		t.Errorf("Stats().ActiveReaders, want 1, got %d", n)
+	}
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		slow.Leave()
+	}()
+
+	lrm.Commit()
+
+	report := lrm.LastWait()
+
+	if report.Generation != 1 || report.Wait < 20*time.Millisecond || len(report.Stragglers) != 1 {
+		t.Fatalf("LastWait(), want one straggler delaying generation 1 by 20ms or more, got %+v", report)
+	}
+
+	if s := report.Stragglers[0]; s.ID != slow.ID() || s.Label != "slow" || s.Wait < 20*time.Millisecond {
+		t.Errorf("LastWait(), want straggler %d (slow), got %+v", slow.ID(), s)
+	}
+
+	if idle.ID() == slow.ID() {
+		t.Errorf("handlers share ID %d", idle.ID())
+	}
+
+	lrm.Commit()
+
+	if report := lrm.LastWait(); len(report.Stragglers) != 0 {
+		t.Errorf("LastWait(), want no stragglers, got %+v", report)
+	}
+}
+
+func TestReaderBarrier(t *testing.T) {
+	blocked := make(chan int, 1)
+	lrm := New(WithReaderBarrier[int, int](func(n int) { blocked <- n }))
+
+	handlers := make([]*ReadHandler[int, int], 3)
+	for i := range handlers {
+		handlers[i] = lrm.NewReadHandler()
+		handlers[i].Enter()
+	}
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		lrm.Commit()
+	}()
+
+	if n := <-blocked; n != 3 {
+		t.Errorf("commit is blocked on %d readers, want 3", n)
+	}
+
+	for _, rh := range handlers {
+		rh.Leave()
+		rh.Close()
+	}
+
+	<-done
+
+	lrm.Commit()
+
+	if n := <-blocked; n != 0 {
+		t.Errorf("commit without readers is blocked on %d readers", n)
+	}
+}
@@ -0,0 +1,115 @@
+package lrmap
+
+import "time"
+
+type (
+	// WatchdogEvent reports a read handler that has kept a commit waiting for longer than the
+	// deadline of the watchdog (see WithReaderWatchdog).
+	WatchdogEvent struct {
+		Generation uint64
+		Straggler
+
+		// Expired is set if the commit has stopped waiting for the handler.
+		Expired bool
+	}
+
+	watchdog struct {
+		deadline time.Duration
+		expire   bool
+		fn       func(WatchdogEvent)
+
+		// expired maps the slots of expired handlers to the epoch they have been expired in.
+		// Commits do not wait for them until they leave that epoch.
+		expired map[*epochSlot]uint64
+
+		// abandoned counts the handlers that have been expired so far.
+		abandoned uint64
+	}
+)
+
+// WithReaderWatchdog makes commits call fn for each read handler that is still entered
+// deadline after the commit has started waiting for it, e.g. to log a reader that forgot to
+// Leave.  fn is called once per handler and commit, with the writer lock held, and must not
+// call into the map.
+//
+// If expire is set, the commit abandons the handler: it stops waiting for it, and neither do
+// later commits until the handler leaves.  This keeps a wedged reader from blocking commits
+// forever.  Any further read of an abandoned handler panics, since the writer reuses the arena
+// the handler still sees; the handler may only Leave (or be closed).  A read that is in
+// progress when the handler is abandoned still races with the writer, so only use a deadline
+// that no legitimate read takes.  Stats reports abandoned handlers.
+func WithReaderWatchdog[K comparable, V any](
+	deadline time.Duration,
+	expire bool,
+	fn func(WatchdogEvent),
+) Option[K, V] {
+	return func(m *LRMap[K, V]) {
+		m.watchdog = watchdog{deadline: deadline, expire: expire, fn: fn, expired: nil}
+	}
+}
+
+// skipExpired removes the readers that have been expired from readers, and forgets about the
+// expired handlers that have left since.
+func (w *watchdog) skipExpired(readers []enteredReader) []enteredReader {
+	if len(w.expired) == 0 {
+		return readers
+	}
+
+	for slot, epoch := range w.expired {
+		if slot.epoch.Load() != epoch {
+			delete(w.expired, slot)
+		}
+	}
+
+	kept := readers[:0]
+
+	for _, r := range readers {
+		if epoch, ok := w.expired[r.slot]; !ok || epoch != r.epoch {
+			kept = append(kept, r)
+		}
+	}
+
+	return kept
+}
+
+// watch returns a function for awaitReaders that reports (and expires) the readers a commit
+// of generation gen, which has started waiting at start, still waits for after the deadline.
+func (w *watchdog) watch(gen uint64, start time.Time, clock Clock) func([]enteredReader) []enteredReader {
+	reported := make(map[*epochSlot]struct{})
+
+	return func(readers []enteredReader) []enteredReader {
+		wait := clock.Now().Sub(start)
+		if wait < w.deadline {
+			return readers
+		}
+
+		kept := readers[:0]
+
+		for _, r := range readers {
+			if _, ok := reported[r.slot]; !ok {
+				reported[r.slot] = struct{}{}
+				w.fn(WatchdogEvent{
+					Generation: gen,
+					Straggler:  Straggler{ReaderInfo: *r.owner, Wait: wait},
+					Expired:    w.expire,
+				})
+			}
+
+			if !w.expire {
+				kept = append(kept, r)
+
+				continue
+			}
+
+			if w.expired == nil {
+				w.expired = make(map[*epochSlot]uint64)
+			}
+
+			w.expired[r.slot] = r.epoch
+			w.abandoned++
+			r.slot.abandoned.Store(r.epoch)
+		}
+
+		return kept
+	}
+}
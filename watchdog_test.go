@@ -0,0 +1,92 @@
+package lrmap
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReaderWatchdog(t *testing.T) {
+	events := make(chan WatchdogEvent, 1)
+	lrm := New(WithReaderWatchdog[string, int](time.Millisecond, false, func(e WatchdogEvent) { events <- e }))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.SetLabel("forgetful")
+	rh.Enter()
+
+	committed := make(chan struct{})
+
+	go func() {
+		lrm.Commit()
+		close(committed)
+	}()
+
+	e := <-events
+	if e.Label != "forgetful" || e.Generation != 1 || e.Expired || e.Wait < time.Millisecond {
+		t.Errorf("unexpected event %+v", e)
+	}
+
+	select {
+	case <-committed:
+		t.Fatalf("commit has not waited for the reader")
+	default:
+	}
+
+	rh.Leave()
+	<-committed
+}
+
+func TestReaderWatchdogExpire(t *testing.T) {
+	var events []WatchdogEvent
+
+	lrm := New(WithReaderWatchdog[string, int](time.Millisecond, true, func(e WatchdogEvent) {
+		events = append(events, e)
+	}))
+
+	rh := lrm.NewReadHandler()
+	defer rh.Close()
+
+	rh.Enter()
+
+	// neither commit blocks on the abandoned reader, and only the first one reports it
+	lrm.Commit()
+	lrm.Commit()
+
+	if len(events) != 1 || !events[0].Expired {
+		t.Fatalf("want one expiry event, got %+v", events)
+	}
+
+	if s := lrm.Stats(); s.AbandonedReaders != 1 || s.AbandonedTotal != 1 {
+		t.Errorf("Stats(): want 1 abandoned reader, got %d (%d in total)", s.AbandonedReaders, s.AbandonedTotal)
+	}
+
+	func() {
+		defer func() {
+			if msg := fmt.Sprint(recover()); !strings.Contains(msg, "abandoned") {
+				t.Errorf("Get() on an abandoned handler, want panic, got %q", msg)
+			}
+		}()
+
+		rh.Get("x")
+	}()
+
+	rh.Leave()
+	lrm.Commit()
+
+	if s := lrm.Stats(); s.AbandonedReaders != 0 || s.AbandonedTotal != 1 {
+		t.Errorf("Stats() after Leave(): want 0 abandoned readers, got %d (%d in total)",
+			s.AbandonedReaders, s.AbandonedTotal)
+	}
+
+	// the handler is usable again
+	rh.Enter()
+	rh.Get("x")
+	rh.Leave()
+
+	if len(lrm.watchdog.expired) != 0 {
+		t.Errorf("want expired handler forgotten after it has left, got %v", lrm.watchdog.expired)
+	}
+}